@@ -0,0 +1,16 @@
+// Package blake2 holds what the blake2b and blake2s packages have in
+// common, so that code can work with either variant.
+package blake2
+
+import "hash"
+
+// Blake2Hash is implemented by the hashes returned by the blake2b and
+// blake2s constructors. It extends hash.Hash with the limits of the
+// variant, so that a caller selecting the algorithm at runtime can
+// validate keys and sizes without knowing which one it holds.
+type Blake2Hash interface {
+	hash.Hash
+
+	// MaxKeySize returns the maximal key length in bytes.
+	MaxKeySize() int
+}
@@ -0,0 +1,38 @@
+package blake2_test
+
+import (
+	"fmt"
+	"testing"
+
+	blake2 "github.com/wheelcomplex/blake2-2"
+	"github.com/wheelcomplex/blake2-2/blake2b"
+	"github.com/wheelcomplex/blake2-2/blake2s"
+)
+
+func TestBlake2Hash(t *testing.T) {
+	for _, v := range []struct {
+		h          blake2.Blake2Hash
+		size       int
+		blockSize  int
+		maxKeySize int
+		expected   string
+	}{
+		{
+			blake2b.New().(blake2.Blake2Hash), 64, 128, 64,
+			"A8ADD4BDDDFD93E4877D2746E62817B116364A1FA7BC148D95090BC7333B3673F82401CF7AA2E4CB1ECD90296E3F14CB5413F8ED77BE73045B13914CDCD6A918",
+		},
+		{
+			blake2s.New().(blake2.Blake2Hash), 32, 64, 32,
+			"606BEEEC743CCBEFF6CBCDF5D5302AA855C256C29B88C8ED331EA1A6BF3C8812",
+		},
+	} {
+		h := v.h
+		if h.Size() != v.size || h.BlockSize() != v.blockSize || h.MaxKeySize() != v.maxKeySize {
+			t.Errorf("bad limits: size=%d, block size=%d, max key size=%d", h.Size(), h.BlockSize(), h.MaxKeySize())
+		}
+		h.Write([]byte("The quick brown fox jumps over the lazy dog"))
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.expected {
+			t.Errorf("bad hash: expected=%s, actual=%s", v.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package blake2b
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrBackendUnavailable is returned by SetBackend for a known backend
+// that this build cannot use.
+var ErrBackendUnavailable = errors.New("blake2b: compression backend unavailable")
+
+// Compression backends selectable by SetBackend.
+const (
+	backendGeneric int32 = iota
+	backendSSE
+	backendAVX2
+)
+
+var backendNames = map[string]int32{
+	"generic": backendGeneric,
+	"sse":     backendSSE,
+	"avx2":    backendAVX2,
+}
+
+// backend holds the compression backend selected by SetBackend. It is
+// read on every compression, possibly concurrently with SetBackend.
+var backend atomic.Int32
+
+// backendAvailable reports whether b can be used. The package has no
+// assembly for Blake2b yet, so only the generic backend is available;
+// "sse" and "avx2" are reserved and always report
+// ErrBackendUnavailable.
+func backendAvailable(b int32) bool {
+	return b == backendGeneric
+}
+
+// SetBackend forces the compression function used by all digests to the
+// named backend: "generic", "sse" or "avx2". It returns
+// ErrBackendUnavailable for a backend that is not available on this
+// build and CPU, and an error for an unknown name, leaving the backend
+// unchanged in both cases. SetBackend is meant for tests and for working
+// around CPU errata. It is safe to call concurrently with hashing; a
+// digest may then use either backend for its next compression.
+func SetBackend(name string) error {
+	b, ok := backendNames[name]
+	if !ok {
+		return fmt.Errorf("blake2b: unknown compression backend %q", name)
+	}
+	if !backendAvailable(b) {
+		return ErrBackendUnavailable
+	}
+	backend.Store(b)
+	return nil
+}
+
+// compress dispatches to the compression function of the selected
+// backend. The calls are direct, rather than through a function value,
+// so that the state and block arguments do not escape to the heap.
+func compress(h *[8]uint64, block []byte, t [2]uint64, last, lastNode bool) {
+	switch backend.Load() {
+	default:
+		compressGeneric(h, block, t, last, lastNode)
+	}
+}
@@ -0,0 +1,50 @@
+package blake2b
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetBackend(t *testing.T) {
+	defer backend.Store(backend.Load())
+	for _, name := range []string{"generic", "sse", "avx2"} {
+		if err := SetBackend(name); err == ErrBackendUnavailable {
+			continue
+		} else if err != nil {
+			t.Fatalf("SetBackend(%q): %v", name, err)
+		}
+		for length, expected := range unkeyed2b {
+			input := make([]byte, length)
+			for i := range input {
+				input[i] = byte(i)
+			}
+			h := New()
+			h.Write(input)
+			if actual := fmt.Sprintf("%0128X", h.Sum(nil)); actual != expected {
+				t.Errorf("bad hash (%s, %d): expected=%s, actual=%s", name, length, expected, actual)
+			}
+		}
+	}
+}
+
+func TestSetBackendUnknown(t *testing.T) {
+	defer backend.Store(backend.Load())
+	if err := SetBackend("neon"); err == nil || err == ErrBackendUnavailable {
+		t.Errorf("expected unknown backend error, got %v", err)
+	}
+}
+
+func TestSetBackendUnavailable(t *testing.T) {
+	defer backend.Store(backend.Load())
+	for _, name := range []string{"sse", "avx2"} {
+		if err := SetBackend("generic"); err != nil {
+			t.Fatalf("SetBackend(generic): %v", err)
+		}
+		if err := SetBackend(name); err != ErrBackendUnavailable {
+			t.Errorf("SetBackend(%q): expected ErrBackendUnavailable, got %v", name, err)
+		}
+		if b := backend.Load(); b != backendGeneric {
+			t.Errorf("SetBackend(%q) changed the backend to %d", name, b)
+		}
+	}
+}
@@ -40,3 +40,93 @@ func BenchmarkSHA256(b *testing.B) {
 func BenchmarkSHA512(b *testing.B) {
 	benchmarkHash(b, sha512.New)
 }
+
+func BenchmarkHashAll256MiB(b *testing.B) {
+	largeInput := make([]byte, 256<<20)
+	b.SetBytes(int64(len(largeInput)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		HashAll(largeInput, Size)
+	}
+}
+
+func BenchmarkWrite256MiB(b *testing.B) {
+	largeInput := make([]byte, 256<<20)
+	b.SetBytes(int64(len(largeInput)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := New()
+		h.Write(largeInput)
+		h.Sum(nil)
+	}
+}
+
+func BenchmarkHashAll32(b *testing.B) {
+	input := make([]byte, 32)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		HashAll(input, 32)
+	}
+}
+
+func BenchmarkWrite32(b *testing.B) {
+	input := make([]byte, 32)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := New()
+		h.Write(input)
+		h.Sum(nil)
+	}
+}
+
+func BenchmarkManyTinyWrites(b *testing.B) {
+	record := make([]byte, 10)
+	b.SetBytes(int64(len(record)) * 100000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := New()
+		for j := 0; j < 100000; j++ {
+			h.Write(record)
+		}
+		h.Sum(nil)
+	}
+}
+
+// benchmarkFinalize measures Sum alone: the digest holds n bytes of
+// input, which Sum pads and compresses without changing the state, and
+// the checksum is appended to a reused buffer.
+func benchmarkFinalize(b *testing.B, n int) {
+	h := New()
+	h.Write(make([]byte, n))
+	out := make([]byte, 0, Size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out = h.Sum(out[:0])
+	}
+}
+
+func BenchmarkFinalizeEmpty(b *testing.B) {
+	benchmarkFinalize(b, 0)
+}
+
+func BenchmarkFinalize64(b *testing.B) {
+	benchmarkFinalize(b, 64)
+}
+
+func BenchmarkSumScratch(b *testing.B) {
+	d := New().(*digest)
+	d.Write(make([]byte, 64))
+	scratch := make([]byte, Size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.SumScratch(scratch)
+	}
+	b.StopTimer()
+	if allocs := testing.AllocsPerRun(10, func() { d.SumScratch(scratch) }); allocs != 0 {
+		b.Fatalf("SumScratch allocated %v times", allocs)
+	}
+}
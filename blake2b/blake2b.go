@@ -1,11 +1,19 @@
 // Package blake2b implements the BLAKE2b hash algorithm.
 //
+// The package has no lazily initialized state: its tables are fixed and
+// its self-test runs in init, so digests may be created and used from
+// any number of goroutines at once. A single digest is not safe for
+// concurrent use.
+//
 // Written by Devi Mandiri <devi.mandiri@gmail.com>
 package blake2b
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/binary"
 	"hash"
+	"io"
 )
 
 // The Blake2b blocksize in bytes.
@@ -14,6 +22,18 @@ const BlockSize = 128
 // The Blake2b maximum key size.
 const KeySize = 64
 
+// The Blake2b maximum digest size in bytes.
+const Size = 64
+
+// The Blake2b salt size in bytes.
+const SaltSize = 16
+
+// The Blake2b personalization size in bytes.
+const PersonalSize = 16
+
+// The size of the buffer used by ReadFrom.
+const readBufSize = 32 * 1024
+
 var (
 	// The Blake2b IV.
 	iv = [8]uint64{
@@ -40,83 +60,203 @@ var (
 	}
 )
 
+// digest holds the state of a Blake2b hash. Its memory use is constant:
+// Write stages input in the fixed buf, compressing blocks as they are
+// complete, and never allocates.
 type digest struct {
 	h      [8]uint64
 	t      [2]uint64
-	f      [2]uint64
 	buf    [2 * BlockSize]byte
 	buflen int
 	key    []byte
+	size   int
+
+	salt     []byte
+	personal []byte
+
+	// Tree hashing parameters, see tree.go and parallel.go.
+	fanout     uint8
+	depth      uint8
+	leafSize   uint32
+	nodeOffset uint64
+	nodeDepth  uint8
+	innerSize  uint8
+	lastNode   bool
+	noKeyBlock bool
+
+	// Input limit, see WithMaxInput.
+	limited  bool
+	maxInput uint64
+	written  uint64
+
+	// Scratch buffer for ReadFrom, allocated on first use.
+	rbuf []byte
+
+	// Staging buffer replacing buf, see WithBufferBlocks.
+	ext []byte
+
+	// Non-standard word order of the output, see WithBigEndianOutput.
+	bigEndian bool
+}
+
+// newDigest returns an unkeyed digest configured for sequential mode.
+func newDigest() *digest {
+	return &digest{size: Size, fanout: 1, depth: 1}
 }
 
 // New returns a new hash.Hash computing the Blake2b checksum.
 func New() hash.Hash {
-	d := new(digest)
+	d := newDigest()
 	d.Reset()
 	return d
 }
 
 // NewKeyed returns a new hash.Hash computing the Blake2b checksum
-// with the given key.
+// with the given key. A key of up to KeySize bytes is used in full;
+// longer keys are truncated to their first KeySize bytes.
 func NewKeyed(key []byte) hash.Hash {
-	d := new(digest)
+	d := newDigest()
 	d.key = key
 	d.Reset()
 	return d
 }
 
+// NewKeyedLong is like NewKeyed but accepts keys of any length: a key
+// longer than KeySize is first replaced by its KeySize byte Blake2b
+// digest, as HMAC does with long keys. Keys of up to KeySize bytes are
+// used unchanged. Unlike with NewKeyed, two long keys sharing their first
+// KeySize bytes give different checksums; such a hash is however not
+// interoperable with implementations of plain keyed Blake2b.
+func NewKeyedLong(key []byte) hash.Hash {
+	if len(key) > KeySize {
+		key = HashAll(key, KeySize)
+	}
+	return NewKeyed(key)
+}
+
+// checkInit panics if d was not created by one of the constructors. A zero
+// digest has no valid parameter block and would produce wrong checksums.
+func (d *digest) checkInit() {
+	if d.size == 0 {
+		panic("blake2b: use of uninitialized digest")
+	}
+}
+
 func (d *digest) Reset() {
-	keylen := len(d.key)
-	if keylen > KeySize {
-		keylen = KeySize
-	}
-	p := make([]byte, BlockSize)
-	p[0] = 64
-	p[1] = uint8(keylen)
-	p[2] = 1
-	p[3] = 1
-
-	d.f[0] = 0
-	d.f[1] = 0
+	d.checkInit()
 	d.t[0] = 0
 	d.t[1] = 0
 	d.buflen = 0
+	d.written = 0
+	d.h = d.paramState()
+	// The key block bypasses Write, so it does not count as input.
+	if d.hasKeyBlock() {
+		block := make([]byte, BlockSize)
+		copy(block[:], d.key[:d.KeyLen()])
+		d.absorb(block)
+	}
+}
+
+// paramState returns the state words of d before any block is
+// compressed: the IV xored with the parameter block.
+func (d *digest) paramState() (h [8]uint64) {
+	var p [BlockSize]byte
+	p[0] = uint8(d.size)
+	p[1] = uint8(d.KeyLen())
+	p[2] = d.fanout
+	p[3] = d.depth
+	binary.LittleEndian.PutUint32(p[4:], d.leafSize)
+	binary.LittleEndian.PutUint64(p[8:], d.nodeOffset)
+	p[16] = d.nodeDepth
+	p[17] = d.innerSize
+	copy(p[32:], d.salt)
+	copy(p[48:], d.personal)
 	for i := 0; i < 8; i++ {
-		d.h[i] = iv[i] ^ binary.LittleEndian.Uint64(p[i*8:])
+		h[i] = iv[i] ^ binary.LittleEndian.Uint64(p[i*8:])
 	}
-	if keylen > 0 {
-		block := make([]byte, BlockSize)
-		copy(block[:], d.key[:keylen])
-		d.Write(block)
+	return
+}
+
+// hasKeyBlock reports whether the input of d starts with a key block.
+func (d *digest) hasKeyBlock() bool {
+	return d.KeyLen() > 0 && !d.noKeyBlock
+}
+
+// Sigma returns the message word permutations of the 12 Blake2b rounds,
+// where rows 10 and 11 repeat rows 0 and 1. The table is returned by
+// value for cross-validation and reduced-round experiments; changing it
+// does not affect hashing.
+func Sigma() [12][16]uint8 {
+	return sigma
+}
+
+// KeyLen returns the key length recorded in the parameter block: the
+// length of the key, truncated to KeySize, or 0 for an unkeyed digest.
+func (d *digest) KeyLen() int {
+	if len(d.key) > KeySize {
+		return KeySize
 	}
+	return len(d.key)
+}
+
+// IsKeyed reports whether d was configured with a non-empty key.
+func (d *digest) IsKeyed() bool {
+	return len(d.key) > 0
 }
 
 func (*digest) BlockSize() int {
 	return 128
 }
 
+// MaxKeySize returns the maximal key length in bytes, KeySize.
+func (*digest) MaxKeySize() int {
+	return KeySize
+}
+
 func (d *digest) Size() int {
-	return 64
+	return d.size
+}
+
+// Compress applies the Blake2b compression function to the caller-owned
+// chain value h, with counter the number of message bytes up to and
+// including block, and last set for the final block.
+//
+// Compress is a low-level primitive for custom constructions: it does no
+// padding or counting, and the caller must initialize h with the IV of
+// RFC 7693 xored with a parameter block. Misuse silently yields digests
+// that are not Blake2b, or insecure ones.
+func Compress(h *[8]uint64, block *[BlockSize]byte, counter [2]uint64, last bool) {
+	compress(h, block[:], counter, last, false)
 }
 
-// compress contains main algorithm of the Blake2b as defined in
+// compressGeneric contains main algorithm of the Blake2b as defined in
 // https://blake2.net/blake2_20130129.pdf
-func (d *digest) compress() {
+//
+// It compresses the first BlockSize bytes of block into h, with t the
+// byte counter including this block. last flags the final block of the
+// input, and lastNode the final block of the last node of a tree level.
+func compressGeneric(h *[8]uint64, block []byte, t [2]uint64, last, lastNode bool) {
 	var m, v [16]uint64
 	for i := 0; i < 16; i++ {
-		m[i] = binary.LittleEndian.Uint64(d.buf[i*8:])
+		m[i] = binary.LittleEndian.Uint64(block[i*8:])
 	}
 	for i := 0; i < 8; i++ {
-		v[i] = d.h[i]
+		v[i] = h[i]
 	}
 	v[8] = iv[0]
 	v[9] = iv[1]
 	v[10] = iv[2]
 	v[11] = iv[3]
-	v[12] = d.t[0] ^ iv[4]
-	v[13] = d.t[1] ^ iv[5]
-	v[14] = d.f[0] ^ iv[6]
-	v[15] = d.f[1] ^ iv[7]
+	v[12] = t[0] ^ iv[4]
+	v[13] = t[1] ^ iv[5]
+	v[14] = iv[6]
+	v[15] = iv[7]
+	if last {
+		v[14] = ^v[14]
+		if lastNode {
+			v[15] = ^v[15]
+		}
+	}
 
 	rotr64 := func(w uint64, c uint) uint64 {
 		return (w >> c) | (w << (64 - c))
@@ -142,60 +282,252 @@ func (d *digest) compress() {
 		G(i, 7, 3, 4, 9, 14)
 	}
 	for i := 0; i < 8; i++ {
-		d.h[i] = d.h[i] ^ v[i] ^ v[i+8]
+		h[i] = h[i] ^ v[i] ^ v[i+8]
 	}
 }
 
 func (d *digest) incrementCounter(inc uint64) {
 	d.t[0] += inc
 	if d.t[0] < inc {
-		d.t[1]--
+		d.t[1]++
 	}
 }
 
+// Write absorbs buf into the hash state. If an input limit is configured
+// and buf would exceed it, only the bytes up to the limit are absorbed and
+// ErrInputLimit is returned together with their count. Writing an empty
+// buf leaves the state untouched.
 func (d *digest) Write(buf []byte) (int, error) {
-	inlen := len(buf)
-	offset := 0
-	for inlen > 0 {
-		left := d.buflen
-		fill := 2*BlockSize - left
-		if inlen > fill {
-			copy(d.buf[left:], buf[:fill])
-			d.buflen += fill
+	d.checkInit()
+	var err error
+	if d.limited && uint64(len(buf)) > d.maxInput-d.written {
+		buf = buf[:d.maxInput-d.written]
+		err = ErrInputLimit
+	}
+	d.written += uint64(len(buf))
+	d.absorb(buf)
+	return len(buf), err
+}
+
+// absorb adds buf to the staging buffer. The buffered blocks are only
+// compressed once more input arrives, since the last of them may need to
+// be flagged as final. Beyond the staging buffer, whole blocks of buf are
+// compressed in place rather than copied.
+func (d *digest) absorb(buf []byte) {
+	stage := d.stage()
+	if n := d.buflen + len(buf); n <= len(stage) {
+		// Small writes only need to be staged.
+		copy(stage[d.buflen:], buf)
+		d.buflen = n
+		return
+	}
+	if d.buflen > 0 {
+		n := copy(stage[d.buflen:], buf)
+		buf = buf[n:]
+		for i := 0; i < len(stage); i += BlockSize {
 			d.incrementCounter(BlockSize)
-			d.compress()
-			copy(d.buf[:BlockSize], d.buf[BlockSize:])
-			d.buflen -= BlockSize
-			offset += fill
-			inlen -= fill
-		} else {
-			copy(d.buf[left:], buf[offset:])
-			d.buflen += inlen
-			offset += inlen
-			inlen -= inlen
+			compress(&d.h, stage[i:], d.t, false, false)
 		}
 	}
-	return 0, nil
+	for len(buf) > len(stage) {
+		d.incrementCounter(BlockSize)
+		compress(&d.h, buf, d.t, false, false)
+		buf = buf[BlockSize:]
+	}
+	d.buflen = copy(stage, buf)
 }
 
-// Sum returns the Blake2b checksum of the data.
-func (d *digest) Sum(buf []byte) []byte {
-	if d.buflen > BlockSize {
+// Flush compresses the buffered blocks that cannot be the final one,
+// leaving at most BlockSize bytes buffered. This moves work out of Sum
+// for latency-sensitive streams; it does not change the checksum.
+func (d *digest) Flush() {
+	d.checkInit()
+	stage := d.stage()
+	n := 0
+	for ; d.buflen-n > BlockSize; n += BlockSize {
 		d.incrementCounter(BlockSize)
-		d.compress()
-		d.buflen -= BlockSize
-		copy(d.buf[:d.buflen], d.buf[BlockSize:])
-	}
-	d.incrementCounter(uint64(d.buflen))
-	d.f[0] = 0xffffffffffffffff
-	j := 2*BlockSize - d.buflen
-	for i := 0; i < j; i++ {
-		d.buf[i+d.buflen] = 0
-	}
-	d.compress()
-	buffer := make([]byte, 64)
+		compress(&d.h, stage[n:], d.t, false, false)
+	}
+	d.buflen = copy(stage, stage[n:d.buflen])
+}
+
+// stage returns the staging buffer of d.
+func (d *digest) stage() []byte {
+	if d.ext != nil {
+		return d.ext
+	}
+	return d.buf[:]
+}
+
+// clone returns a copy of d that does not share its staging buffer.
+func (d *digest) clone() digest {
+	dd := *d
+	if d.ext != nil {
+		dd.ext = append([]byte(nil), d.ext...)
+	}
+	return dd
+}
+
+// ReadFrom absorbs data from r until EOF. It returns the number of bytes
+// absorbed and any error encountered other than io.EOF. A *bufio.Reader
+// is hashed straight from its buffer.
+func (d *digest) ReadFrom(r io.Reader) (int64, error) {
+	if br, ok := r.(*bufio.Reader); ok {
+		return d.readFromBufio(br)
+	}
+	if d.rbuf == nil {
+		d.rbuf = make([]byte, readBufSize)
+	}
+	var total int64
+	for {
+		n, err := r.Read(d.rbuf)
+		if n > 0 {
+			w, werr := d.Write(d.rbuf[:n])
+			total += int64(w)
+			if werr != nil {
+				return total, werr
+			}
+		}
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// readFromBufio absorbs the buffered data of br, refilling it until EOF.
+func (d *digest) readFromBufio(br *bufio.Reader) (int64, error) {
+	var total int64
+	for {
+		if br.Buffered() == 0 {
+			if _, err := br.Peek(1); err != nil {
+				if err == io.EOF {
+					return total, nil
+				}
+				return total, err
+			}
+		}
+		window, _ := br.Peek(br.Buffered())
+		w, err := d.Write(window)
+		br.Discard(w)
+		total += int64(w)
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Sum appends the Blake2b checksum of the data to buf. It does not
+// change the underlying hash state.
+func (d *digest) Sum(buf []byte) []byte {
+	d.checkInit()
+	dd := *d
+	sum := dd.checkSum()
+	return append(buf, sum[:d.size]...)
+}
+
+// Checkpoint appends the checksum of the data written so far to dst,
+// without changing the underlying hash state. It does not allocate if
+// dst has room for Size() more bytes.
+func (d *digest) Checkpoint(dst []byte) []byte {
+	return d.Sum(dst)
+}
+
+// SumScratch writes the checksum of the data to out[:Size()] and returns
+// that slice, leaving the hash state unchanged. Unlike Sum, it never
+// appends, so a hot loop can reuse one buffer for every checksum without
+// allocating. It panics if cap(out) is less than Size().
+func (d *digest) SumScratch(out []byte) []byte {
+	if cap(out) < d.size {
+		panic("blake2b: scratch buffer too small")
+	}
+	return d.Sum(out[:0])
+}
+
+// SumWriter returns a reader over the checksum of the data written so
+// far, for streaming it out with io.Copy. The checksum is computed from a
+// copy when SumWriter is called, so later writes do not affect the
+// reader.
+func (d *digest) SumWriter() io.Reader {
+	return bytes.NewReader(d.Sum(nil))
+}
+
+// Peek returns the checksum of the data written so far. Like Sum, it
+// leaves the hash state unchanged, so a caller can show an intermediate
+// result and keep writing:
+//
+//	h.Write(a)
+//	show(h.(interface{ Peek() []byte }).Peek()) // checksum of a
+//	h.Write(b)
+//	show(h.Sum(nil))                            // checksum of a and b
+func (d *digest) Peek() []byte {
+	return d.Sum(nil)
+}
+
+// Sum64 returns the first 8 bytes of the checksum as a little-endian
+// uint64. This is a truncation of the configured digest, not a 64-bit
+// Blake2b; digests shorter than 8 bytes are padded with zeros.
+func (d *digest) Sum64() uint64 {
+	var b [8]byte
+	copy(b[:], d.Sum(make([]byte, 0, Size)))
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// Sum64BE is like Sum64 but reads the bytes as a big-endian uint64.
+func (d *digest) Sum64BE() uint64 {
+	var b [8]byte
+	copy(b[:], d.Sum(make([]byte, 0, Size)))
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// SumBinary appends the checksum of the data to dst like Sum, with the
+// state words serialized in the given byte order. Blake2b specifies
+// binary.LittleEndian, which gives the output of Sum; the order passed
+// here takes precedence over WithBigEndianOutput.
+func (d *digest) SumBinary(dst []byte, order binary.ByteOrder) []byte {
+	d.checkInit()
+	dd := *d
+	dd.checkSum()
+	var sum [Size]byte
+	for i := 0; i < 8; i++ {
+		order.PutUint64(sum[i*8:], dd.h[i])
+	}
+	return append(dst, sum[:d.size]...)
+}
+
+// checkSum finalizes the hash state and returns the full-length checksum.
+func (d *digest) checkSum() [Size]byte {
+	if d.buflen == 0 && d.hasKeyBlock() {
+		// A keyed digest only runs out of staged input when it starts
+		// from a KeyedState and nothing was written: the key block,
+		// compressed there as not final, must be redone as the final one.
+		var block [BlockSize]byte
+		copy(block[:], d.key[:d.KeyLen()])
+		d.h = d.paramState()
+		compress(&d.h, block[:], d.t, true, d.lastNode)
+	} else {
+		stage := d.stage()
+		n := 0
+		for ; d.buflen-n > BlockSize; n += BlockSize {
+			d.incrementCounter(BlockSize)
+			compress(&d.h, stage[n:], d.t, false, false)
+		}
+		// The final block is padded in a zeroed copy: the staging buffer
+		// past buflen holds stale input, and is never written here.
+		var block [BlockSize]byte
+		copy(block[:], stage[n:d.buflen])
+		d.incrementCounter(uint64(d.buflen - n))
+		compress(&d.h, block[:], d.t, true, d.lastNode)
+	}
+	var sum [Size]byte
 	for i := 0; i < 8; i++ {
-		binary.LittleEndian.PutUint64(buffer[i*8:], d.h[i])
+		if d.bigEndian {
+			binary.BigEndian.PutUint64(sum[i*8:], d.h[i])
+		} else {
+			binary.LittleEndian.PutUint64(sum[i*8:], d.h[i])
+		}
 	}
-	return append(buf, buffer[:]...)
+	return sum
 }
@@ -1,7 +1,11 @@
 package blake2b
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"hash"
+	"io"
 	"testing"
 )
 
@@ -542,6 +546,61 @@ func TestBlake2b(t *testing.T) {
 	}
 }
 
+func TestWriteLength(t *testing.T) {
+	input := make([]byte, 1000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	expected := "C11E1C0340BD7E5A1B275F1230C962FAD215ECB1391486E74E31B960A2F2996381A5FAD092DA06841D5F26E38F6ECFEAF441ACBCD1C2DE61AEF121E7927175F5"
+
+	for _, chunk := range []int{1, 7, 127, 128, 129, 256, 300, 1000} {
+		h := New()
+		for i := 0; i < len(input); i += chunk {
+			end := i + chunk
+			if end > len(input) {
+				end = len(input)
+			}
+			if n, err := h.Write(input[i:end]); n != end-i || err != nil {
+				t.Fatalf("Write(%d): n=%d, err=%v", end-i, n, err)
+			}
+		}
+		actual := fmt.Sprintf("%X", h.Sum(nil))
+		if actual != expected {
+			t.Errorf("bad hash (chunk %d): expected=%s, actual=%s", chunk, expected, actual)
+		}
+	}
+}
+
+func TestSumIdempotent(t *testing.T) {
+	h := New()
+	h.Write([]byte("one two"))
+	first := h.Sum(nil)
+	if second := h.Sum(nil); !bytes.Equal(first, second) {
+		t.Errorf("Sum changed the hash state: first=%X, second=%X", first, second)
+	}
+
+	h.Write([]byte(" three"))
+	ref := New()
+	ref.Write([]byte("one two three"))
+	if actual, expected := h.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash after Sum: expected=%X, actual=%X", expected, actual)
+	}
+}
+
+func TestStreamingBlake2b(t *testing.T) {
+	for len, expected := range unkeyed2b {
+		h := New()
+		for i := 0; i < len; i++ {
+			h.Write([]byte{byte(i)})
+		}
+		actual := fmt.Sprintf("%0128X", h.Sum(nil))
+
+		if actual != expected {
+			t.Errorf("bad streaming hash (%d): expected=%s, actual=%s", len, expected, actual)
+		}
+	}
+}
+
 func ExampleNew() {
 	h := New()
 	h.Write([]byte("one two three"))
@@ -576,6 +635,50 @@ func TestKeyedBlake2b(t *testing.T) {
 	}
 }
 
+// TestKeyedShortMessages finalizes keyed digests holding less than one
+// block of message after the key block, where the counter must include
+// the key block exactly once. The vectors use the key "short key" and the
+// input bytes 0, 1, ..., and come from Python's hashlib.blake2b.
+func TestKeyedShortMessages(t *testing.T) {
+	key := []byte("short key")
+	for _, v := range []struct {
+		length   int
+		expected string
+	}{
+		{0, "7298FF285E9BD4DC2777D69EA90C2B92B71EC884F9EBEE78333557365CFB99B9"},
+		{1, "750C364DD30071B060AF0C3952EF7ABBD9F32A85C178DF14C23EA53126C9D88E"},
+		{63, "4AD23619E797694996F913FE5B9687C3589B56D8063A087F1BB31C1077270BB3"},
+	} {
+		input := make([]byte, v.length)
+		for i := range input {
+			input[i] = byte(i)
+		}
+		for _, opts := range [][]Option{
+			{WithKey(key), WithSize(32)},
+			{WithKey(key), WithSize(32), WithBufferBlocks(1)},
+		} {
+			h, _ := NewWith(opts...)
+			for i := range input {
+				h.Write(input[i : i+1])
+			}
+			if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.expected {
+				t.Errorf("bad hash (%d): expected=%s, actual=%s", v.length, v.expected, actual)
+			}
+			if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.expected {
+				t.Errorf("bad second hash (%d): expected=%s, actual=%s", v.length, v.expected, actual)
+			}
+			h.Reset()
+			h.Write(input)
+			if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.expected {
+				t.Errorf("bad hash after Reset (%d): expected=%s, actual=%s", v.length, v.expected, actual)
+			}
+		}
+		if actual := fmt.Sprintf("%X", MAC(key, input, 32)); actual != v.expected {
+			t.Errorf("bad MAC (%d): expected=%s, actual=%s", v.length, v.expected, actual)
+		}
+	}
+}
+
 func ExampleNewKeyed() {
 	h := NewKeyed([]byte("my secret"))
 	h.Write([]byte("one two three"))
@@ -584,3 +687,472 @@ func ExampleNewKeyed() {
 	// Output:
 	// FC182724DC024B95F62E606859AC806E4EDCA09A927F6BC8BCCD07DADE3E4F26FC9D041661407527AADEF517A173E19BAB5C389217C29A08BE9731AEC83C02C3
 }
+
+func TestCounterCarry(t *testing.T) {
+	d := New().(*digest)
+	d.t[0] = 1<<64 - 64
+	d.incrementCounter(BlockSize)
+	if d.t[0] != 64 || d.t[1] != 1 {
+		t.Errorf("bad counter after wrap: t=%v", d.t)
+	}
+	d.incrementCounter(BlockSize)
+	if d.t[0] != 192 || d.t[1] != 1 {
+		t.Errorf("bad counter after wrap: t=%v", d.t)
+	}
+
+	d = New().(*digest)
+	d.t[0] = 1<<64 - 1
+	d.incrementCounter(0)
+	if d.t[0] != 1<<64-1 || d.t[1] != 0 {
+		t.Errorf("bad counter without carry: t=%v", d.t)
+	}
+}
+
+func TestCounterCarryHash(t *testing.T) {
+	input := make([]byte, 300)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+
+	h := New()
+	h.(*digest).t[0] = 1<<64 - 64
+	h.Write(input)
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+
+	expected := "36C9EADC818F68F503E4AFF7F7455CEF004274F91C3F17C898346F583CD3895ED9E57C3597D12247DB1EB4F57391168CD37245C5CE2FDA0CBCC97849D1973AD7"
+	if actual != expected {
+		t.Errorf("bad hash across counter wrap: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	input := []byte("one two three")
+	d := New().(*digest)
+	d.Write(input[:3])
+	first := d.Checkpoint(nil)
+	d.Write(input[3:7])
+	second := d.Checkpoint(make([]byte, 0, Size))
+	d.Write(input[7:])
+
+	if bytes.Equal(first, second) {
+		t.Error("checkpoints at different points are equal")
+	}
+	ref := New()
+	ref.Write(input[:3])
+	if expected := ref.Sum(nil); !bytes.Equal(first, expected) {
+		t.Errorf("bad checkpoint: expected=%X, actual=%X", expected, first)
+	}
+	ref.Reset()
+	ref.Write(input)
+	if actual, expected := d.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash after checkpoints: expected=%X, actual=%X", expected, actual)
+	}
+
+	dst := make([]byte, 0, Size)
+	if allocs := testing.AllocsPerRun(10, func() { d.Checkpoint(dst) }); allocs != 0 {
+		t.Errorf("Checkpoint allocated %v times", allocs)
+	}
+}
+
+func TestSumScratch(t *testing.T) {
+	h, _ := NewWith(WithSize(32))
+	d := h.(*digest)
+	d.Write([]byte("one two three"))
+	scratch := make([]byte, 5, Size)
+	actual := d.SumScratch(scratch)
+	if expected := d.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+	}
+	if &actual[0] != &scratch[0] {
+		t.Error("SumScratch did not use the scratch buffer")
+	}
+	if allocs := testing.AllocsPerRun(10, func() { d.SumScratch(scratch) }); allocs != 0 {
+		t.Errorf("SumScratch allocated %v times", allocs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for short scratch buffer")
+		}
+	}()
+	d.SumScratch(make([]byte, 0, 31))
+}
+
+func TestSumWriter(t *testing.T) {
+	d := New().(*digest)
+	d.Write([]byte("one two three"))
+	expected := d.Sum(nil)
+	r := d.SumWriter()
+	d.Write([]byte("four"))
+
+	var actual []byte
+	chunk := make([]byte, 5)
+	for {
+		n, err := r.Read(chunk)
+		actual = append(actual, chunk[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+	}
+
+	var buf bytes.Buffer
+	io.Copy(&buf, d.SumWriter())
+	if expected := d.Sum(nil); !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("bad copied hash: expected=%X, actual=%X", expected, buf.Bytes())
+	}
+}
+
+func TestSumStaleBuffer(t *testing.T) {
+	input := make([]byte, 2*BlockSize)
+	for i := range input {
+		input[i] = byte(i)
+	}
+	d := New().(*digest)
+	for _, n := range []int{BlockSize + 1, BlockSize + 2, 2*BlockSize - 1} {
+		// Leave stale bytes in the high half of the staging buffer.
+		d.Reset()
+		d.Write(bytes.Repeat([]byte{0xff}, 2*BlockSize))
+		d.Reset()
+		d.Write(input[:n])
+		if d.buflen != n {
+			t.Fatalf("bad buffer length: expected=%d, actual=%d", n, d.buflen)
+		}
+		if actual := fmt.Sprintf("%X", d.Sum(nil)); actual != unkeyed2b[n] {
+			t.Errorf("bad hash (%d): expected=%s, actual=%s", n, unkeyed2b[n], actual)
+		}
+		if !bytes.Equal(d.buf[n:], bytes.Repeat([]byte{0xff}, 2*BlockSize-n)) {
+			t.Errorf("Sum wrote to the staging buffer (%d)", n)
+		}
+	}
+}
+
+func TestFlush(t *testing.T) {
+	input := make([]byte, 1000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	expected := "C11E1C0340BD7E5A1B275F1230C962FAD215ECB1391486E74E31B960A2F2996381A5FAD092DA06841D5F26E38F6ECFEAF441ACBCD1C2DE61AEF121E7927175F5"
+
+	for _, chunk := range []int{1, 100, BlockSize, BlockSize + 1, 2 * BlockSize, 1000} {
+		d := New().(*digest)
+		d.Flush()
+		for i := 0; i < len(input); i += chunk {
+			end := i + chunk
+			if end > len(input) {
+				end = len(input)
+			}
+			d.Write(input[i:end])
+			d.Flush()
+			if d.buflen > BlockSize {
+				t.Fatalf("Flush left %d bytes buffered", d.buflen)
+			}
+			d.Flush()
+		}
+		if actual := fmt.Sprintf("%X", d.Sum(nil)); actual != expected {
+			t.Errorf("bad hash (chunk %d): expected=%s, actual=%s", chunk, expected, actual)
+		}
+	}
+}
+
+func TestStreamingMemory(t *testing.T) {
+	total := 100 << 20
+	if testing.Short() {
+		total = 1 << 20
+	}
+	chunk := make([]byte, 1<<20+1)
+	d := New().(*digest)
+	allocs := testing.AllocsPerRun(1, func() {
+		d.Reset()
+		for n := 0; n < total; n += len(chunk) {
+			d.Write(chunk)
+			if d.buflen > len(d.buf) || d.ext != nil {
+				t.Fatalf("staging buffer grew to %d bytes", d.buflen)
+			}
+		}
+		d.Checkpoint(chunk[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("hashing %d bytes allocated %v times", total, allocs)
+	}
+}
+
+func TestSumBinary(t *testing.T) {
+	h, _ := NewWith(WithSize(20))
+	h.Write([]byte("abc"))
+	d := h.(*digest)
+
+	if actual, expected := d.SumBinary(nil, binary.LittleEndian), d.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad little-endian hash: expected=%X, actual=%X", expected, actual)
+	}
+	dd := *d
+	words := dd.checkSum()
+	expected := make([]byte, 20)
+	for i := range expected {
+		expected[i] = words[i/8*8+7-i%8]
+	}
+	if actual := d.SumBinary([]byte("x"), binary.BigEndian); !bytes.Equal(actual, append([]byte("x"), expected...)) {
+		t.Errorf("bad big-endian hash: expected=%X, actual=%X", expected, actual[1:])
+	}
+}
+
+func TestPeek(t *testing.T) {
+	a, b := []byte("first part, "), []byte("second part")
+	d := New().(*digest)
+	d.Write(a)
+	first := d.Peek()
+	d.Write(b)
+	second := d.Peek()
+
+	if expected := HashAll(a, Size); !bytes.Equal(first, expected) {
+		t.Errorf("bad first peek: expected=%X, actual=%X", expected, first)
+	}
+	if expected := HashAll(append(a, b...), Size); !bytes.Equal(second, expected) {
+		t.Errorf("bad second peek: expected=%X, actual=%X", expected, second)
+	}
+	if actual := d.Sum(nil); !bytes.Equal(actual, second) {
+		t.Errorf("Sum after Peek differs: expected=%X, actual=%X", second, actual)
+	}
+}
+
+func TestSum64(t *testing.T) {
+	for _, v := range []struct {
+		input string
+		le    uint64
+		be    uint64
+	}{
+		{"", 0x03590142f7026a78, 0x786a02f742015903},
+		{"one two three", 0x0d7755091e90d25b, 0x5bd2901e0955770d},
+	} {
+		d := New().(*digest)
+		d.Write([]byte(v.input))
+		if actual := d.Sum64(); actual != v.le {
+			t.Errorf("bad Sum64 (%q): expected=%#x, actual=%#x", v.input, v.le, actual)
+		}
+		if actual := d.Sum64BE(); actual != v.be {
+			t.Errorf("bad Sum64BE (%q): expected=%#x, actual=%#x", v.input, v.be, actual)
+		}
+	}
+
+	h, _ := NewWith(WithSize(4))
+	h.Write([]byte("abc"))
+	if actual := h.(*digest).Sum64(); actual != 0x48629063 {
+		t.Errorf("bad Sum64 of short digest: %#x", actual)
+	}
+}
+
+func TestUninitializedDigest(t *testing.T) {
+	for name, f := range map[string]func(d *digest){
+		"Write": func(d *digest) { d.Write([]byte("abc")) },
+		"Sum":   func(d *digest) { d.Sum(nil) },
+		"Reset": func(d *digest) { d.Reset() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s on a zero digest did not panic", name)
+				}
+			}()
+			var d digest
+			f(&d)
+		}()
+	}
+}
+
+func TestKeySizeBoundary(t *testing.T) {
+	key := make([]byte, KeySize+1)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	input := []byte{0, 1, 2}
+	expected := keyed2B[len(input)]
+
+	h := NewKeyed(key[:KeySize])
+	h.Write(input)
+	if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != expected {
+		t.Errorf("bad hash with KeySize key: expected=%s, actual=%s", expected, actual)
+	}
+
+	h = NewKeyed(key)
+	h.Write(input)
+	if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != expected {
+		t.Errorf("oversized key was not truncated: expected=%s, actual=%s", expected, actual)
+	}
+
+	h = NewKeyed(key[:KeySize-1])
+	h.Write(input)
+	if actual := fmt.Sprintf("%X", h.Sum(nil)); actual == expected {
+		t.Error("key of KeySize-1 bytes hashed like a full key")
+	}
+}
+
+func TestManyTinyWrites(t *testing.T) {
+	input := make([]byte, 10*100000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	h := New()
+	for i := 0; i < len(input); i += 10 {
+		h.Write(input[i : i+10])
+	}
+	if actual, expected := h.Sum(nil), HashAll(input, Size); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash of tiny writes: expected=%X, actual=%X", expected, actual)
+	}
+}
+
+func TestNewKeyedLong(t *testing.T) {
+	key := make([]byte, 100)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	input := []byte("abc")
+	sum := func(h hash.Hash) []byte {
+		h.Write(input)
+		return h.Sum(nil)
+	}
+
+	for _, n := range []int{0, 1, KeySize} {
+		if actual, expected := sum(NewKeyedLong(key[:n])), sum(NewKeyed(key[:n])); !bytes.Equal(actual, expected) {
+			t.Errorf("short key (%d) was changed: expected=%X, actual=%X", n, expected, actual)
+		}
+	}
+	long := sum(NewKeyedLong(key))
+	if expected := sum(NewKeyed(HashAll(key, KeySize))); !bytes.Equal(long, expected) {
+		t.Errorf("long key was not pre-hashed: expected=%X, actual=%X", expected, long)
+	}
+	if truncated := sum(NewKeyed(key)); bytes.Equal(long, truncated) {
+		t.Error("long key was truncated")
+	}
+	if other := sum(NewKeyedLong(key[:KeySize+1])); bytes.Equal(long, other) {
+		t.Error("long keys with a common prefix hashed identically")
+	}
+}
+
+func TestKeyLen(t *testing.T) {
+	for _, v := range []struct {
+		h        hash.Hash
+		expected int
+	}{
+		{New(), 0},
+		{NewKeyed(nil), 0},
+		{NewKeyed([]byte("key")), 3},
+		{NewKeyed(make([]byte, KeySize)), KeySize},
+		{NewKeyed(make([]byte, KeySize+1)), KeySize},
+	} {
+		d := v.h.(*digest)
+		if actual := d.KeyLen(); actual != v.expected {
+			t.Errorf("bad key length: expected=%d, actual=%d", v.expected, actual)
+		}
+		// The key block is still buffered, so h holds the parameter block.
+		if recorded := int(byte((d.h[0] ^ iv[0]) >> 8)); recorded != v.expected {
+			t.Errorf("bad key length in parameter block: expected=%d, actual=%d", v.expected, recorded)
+		}
+	}
+}
+
+func TestIsKeyed(t *testing.T) {
+	keyed, _ := NewWith(WithKey([]byte("key")))
+	unkeyed, _ := NewWith(WithSize(32))
+	for _, v := range []struct {
+		h        hash.Hash
+		expected bool
+	}{
+		{New(), false},
+		{NewKeyed(nil), false},
+		{NewKeyed([]byte{}), false},
+		{unkeyed, false},
+		{NewKeyed([]byte("key")), true},
+		{NewKeyedLong(make([]byte, 100)), true},
+		{keyed, true},
+	} {
+		if actual := v.h.(*digest).IsKeyed(); actual != v.expected {
+			t.Errorf("bad IsKeyed: expected=%v, actual=%v", v.expected, actual)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	input := make([]byte, 300)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	h := iv
+	h[0] ^= 0x01010000 | Size
+	var counter [2]uint64
+	for rest := input; len(rest) > 0; {
+		var block [BlockSize]byte
+		n := copy(block[:], rest)
+		rest = rest[n:]
+		counter[0] += uint64(n)
+		Compress(&h, &block, counter, len(rest) == 0)
+	}
+
+	var sum [Size]byte
+	for i, w := range h {
+		binary.LittleEndian.PutUint64(sum[i*8:], w)
+	}
+	if expected := HashAll(input, Size); !bytes.Equal(sum[:], expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, sum)
+	}
+}
+
+func TestEmptyWrite(t *testing.T) {
+	input := make([]byte, 1000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	expected := "C11E1C0340BD7E5A1B275F1230C962FAD215ECB1391486E74E31B960A2F2996381A5FAD092DA06841D5F26E38F6ECFEAF441ACBCD1C2DE61AEF121E7927175F5"
+
+	h := New()
+	d := h.(*digest)
+	start := 0
+	for _, end := range []int{0, 1, BlockSize, 2 * BlockSize, 2*BlockSize + 1, 1000} {
+		h.Write(input[start:end])
+		start = end
+		for _, empty := range [][]byte{nil, {}} {
+			before := *d
+			if n, err := h.Write(empty); n != 0 || err != nil {
+				t.Fatalf("Write(%v): n=%d, err=%v", empty, n, err)
+			}
+			if d.h != before.h || d.t != before.t || d.buflen != before.buflen {
+				t.Errorf("empty write after %d bytes changed the state", end)
+			}
+		}
+	}
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+	if actual != expected {
+		t.Errorf("bad hash: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+// The permutations from section 2.7 of RFC 7693.
+var specSigma = [10][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+func TestSigma(t *testing.T) {
+	table := Sigma()
+	for r, row := range table {
+		if row != specSigma[r%10] {
+			t.Errorf("bad permutation (round %d): expected=%v, actual=%v", r, specSigma[r%10], row)
+		}
+	}
+	table[0][0] = 1
+	if Sigma()[0][0] != 0 {
+		t.Error("modifying the returned table changed sigma")
+	}
+}
@@ -0,0 +1,22 @@
+package blake2b
+
+import "encoding/binary"
+
+// BloomHashes returns k indexes in [0, m) of data for a Bloom filter of m
+// bits, derived from a single 16-byte Blake2b digest by double hashing:
+// with h1 and h2 the two little-endian halves of the digest, index i is
+// (h1 + i*h2) mod m. It panics if k is negative or if m is not between 1
+// and 2^32.
+func BloomHashes(data []byte, k, m int) []uint32 {
+	if k < 0 || m < 1 || uint64(m) > 1<<32 {
+		panic("blake2b: invalid argument to BloomHashes")
+	}
+	sum := HashAll(data, 16)
+	h1 := binary.LittleEndian.Uint64(sum)
+	h2 := binary.LittleEndian.Uint64(sum[8:])
+	indexes := make([]uint32, k)
+	for i := range indexes {
+		indexes[i] = uint32((h1 + uint64(i)*h2) % uint64(m))
+	}
+	return indexes
+}
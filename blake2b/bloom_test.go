@@ -0,0 +1,51 @@
+package blake2b
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBloomHashes(t *testing.T) {
+	for _, m := range []int{1, 7, 1000, 1 << 20} {
+		indexes := BloomHashes([]byte("abc"), 10, m)
+		if len(indexes) != 10 {
+			t.Fatalf("bad index count: %d", len(indexes))
+		}
+		for _, i := range indexes {
+			if int(i) >= m {
+				t.Errorf("index %d out of range [0, %d)", i, m)
+			}
+		}
+		if again := BloomHashes([]byte("abc"), 10, m); !reflect.DeepEqual(again, indexes) {
+			t.Errorf("indexes are not deterministic: %v, %v", indexes, again)
+		}
+	}
+	if indexes := BloomHashes([]byte("abc"), 0, 10); len(indexes) != 0 {
+		t.Errorf("bad indexes for k=0: %v", indexes)
+	}
+}
+
+func TestBloomHashesSpread(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint(BloomHashes([]byte(fmt.Sprint(i)), 4, 1<<20))
+		if seen[key] {
+			t.Errorf("duplicate index set for %d: %s", i, key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestBloomHashesInvalid(t *testing.T) {
+	for _, v := range []struct{ k, m int }{{-1, 10}, {1, 0}, {1, -1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for k=%d, m=%d", v.k, v.m)
+				}
+			}()
+			BloomHashes(nil, v.k, v.m)
+		}()
+	}
+}
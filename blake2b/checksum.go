@@ -0,0 +1,124 @@
+package blake2b
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteChecksumFile writes entries to w in the coreutils format, one
+// FormatB2Sum line per entry, sorted by filename. File names with
+// backslashes or newlines are escaped like b2sum does. It returns an
+// error, before writing anything, for an empty file name or digest,
+// which ReadChecksumFile could not parse back.
+func WriteChecksumFile(w io.Writer, entries map[string][]byte) error {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" || len(entries[name]) == 0 {
+			return fmt.Errorf("blake2b: empty file name or digest for %q", name)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		if _, err := fmt.Fprintln(bw, FormatB2Sum(entries[name], name)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadChecksumFile parses the lines written by WriteChecksumFile, or by
+// b2sum, with ParseB2Sum and returns the digests keyed by filename.
+// Empty lines are skipped.
+func ReadChecksumFile(r io.Reader) (map[string][]byte, error) {
+	entries := make(map[string][]byte)
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		if s.Text() == "" {
+			continue
+		}
+		digest, name, err := ParseB2Sum(s.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%w on line %d", err, line)
+		}
+		entries[name] = digest
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// b2sumEscaper escapes file names as b2sum does: a name containing a
+// backslash or newline is written with both escaped and the whole line
+// prefixed with a backslash.
+var b2sumEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// FormatB2Sum returns the line, without its trailing newline, that the
+// b2sum command prints for filename with the given digest: the lowercase
+// hex digest, two spaces and the file name, escaped like b2sum does for
+// names with backslashes or newlines.
+func FormatB2Sum(digest []byte, filename string) string {
+	line := hex.EncodeToString(digest) + "  " + b2sumEscaper.Replace(filename)
+	if strings.ContainsAny(filename, "\\\n") {
+		line = `\` + line
+	}
+	return line
+}
+
+// ParseB2Sum parses a line printed by b2sum, or by FormatB2Sum, into its
+// digest and file name. It accepts the " *" separator that b2sum uses in
+// binary mode, and undoes the escaping of file names.
+func ParseB2Sum(line string) (digest []byte, filename string, err error) {
+	escaped := strings.HasPrefix(line, `\`)
+	if escaped {
+		line = line[1:]
+	}
+	i := strings.IndexByte(line, ' ')
+	if i <= 0 || i+2 >= len(line) || (line[i+1] != ' ' && line[i+1] != '*') {
+		return nil, "", errors.New("blake2b: malformed b2sum line")
+	}
+	digest, err = hex.DecodeString(line[:i])
+	if err != nil {
+		return nil, "", errors.New("blake2b: malformed hex digest")
+	}
+	filename = line[i+2:]
+	if escaped {
+		if filename, err = unescapeB2Sum(filename); err != nil {
+			return nil, "", err
+		}
+	}
+	return digest, filename, nil
+}
+
+// unescapeB2Sum reverses b2sumEscaper.
+func unescapeB2Sum(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i++; i == len(s) {
+			return "", errors.New("blake2b: malformed b2sum escape")
+		}
+		switch s[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			return "", errors.New("blake2b: malformed b2sum escape")
+		}
+	}
+	return b.String(), nil
+}
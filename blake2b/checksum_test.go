@@ -0,0 +1,176 @@
+package blake2b
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestChecksumFile(t *testing.T) {
+	entries := map[string][]byte{
+		"b.txt":          HashAll([]byte("b"), Size),
+		"a.txt":          HashAll([]byte("a"), Size),
+		"with space.txt": HashAll(nil, 32),
+	}
+
+	var buf bytes.Buffer
+	if err := WriteChecksumFile(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "  a.txt") {
+		t.Errorf("bad checksum file:\n%s", buf.String())
+	}
+
+	parsed, err := ReadChecksumFile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(entries) {
+		t.Fatalf("bad entry count: expected=%d, actual=%d", len(entries), len(parsed))
+	}
+	for name, digest := range entries {
+		if !bytes.Equal(parsed[name], digest) {
+			t.Errorf("bad digest for %q: expected=%X, actual=%X", name, digest, parsed[name])
+		}
+	}
+}
+
+func TestReadChecksumFileMalformed(t *testing.T) {
+	for _, input := range []string{
+		"abcd\n",
+		"abcd file\n",
+		"abcd  \n",
+		"  file\n",
+		"xyz1  file\n",
+		"abc  file\n",
+	} {
+		if _, err := ReadChecksumFile(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+// b2sumLines were printed by b2sum (GNU coreutils) 9.1.
+var b2sumLines = []struct {
+	line     string
+	input    string
+	size     int
+	filename string
+}{
+	{"ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923  abc.txt", "abc", 64, "abc.txt"},
+	{"bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319  abc.txt", "abc", 32, "abc.txt"},
+	{`\442a44457137672b3218c1007dc8f76a  we\\ird`, "x", 16, `we\ird`},
+}
+
+func TestFormatB2Sum(t *testing.T) {
+	for _, v := range b2sumLines {
+		if actual := FormatB2Sum(HashAll([]byte(v.input), v.size), v.filename); actual != v.line {
+			t.Errorf("bad line: expected=%s, actual=%s", v.line, actual)
+		}
+		digest, filename, err := ParseB2Sum(v.line)
+		if err != nil {
+			t.Fatalf("ParseB2Sum(%q): %v", v.line, err)
+		}
+		if expected := HashAll([]byte(v.input), v.size); !bytes.Equal(digest, expected) || filename != v.filename {
+			t.Errorf("bad parse of %q: digest=%X, filename=%q", v.line, digest, filename)
+		}
+	}
+}
+
+func TestParseB2SumRoundTrip(t *testing.T) {
+	digest := HashAll([]byte("abc"), 20)
+	for _, filename := range []string{"a", "with  spaces", "new\nline", `back\slash`, `\n`, "*star"} {
+		line := FormatB2Sum(digest, filename)
+		actualDigest, actualName, err := ParseB2Sum(line)
+		if err != nil {
+			t.Fatalf("ParseB2Sum(%q): %v", line, err)
+		}
+		if !bytes.Equal(actualDigest, digest) || actualName != filename {
+			t.Errorf("bad round trip of %q: digest=%X, filename=%q", filename, actualDigest, actualName)
+		}
+	}
+	if _, filename, err := ParseB2Sum(hex.EncodeToString(digest) + " *bin"); err != nil || filename != "bin" {
+		t.Errorf("binary mode line: filename=%q, err=%v", filename, err)
+	}
+}
+
+func TestParseB2SumMalformed(t *testing.T) {
+	for _, line := range []string{"", "abcd", "abcd  ", "abcd filename", "zz  filename", "  filename", `\abcd  bad\escape`, `\abcd  trailing\`} {
+		if _, _, err := ParseB2Sum(line); err == nil {
+			t.Errorf("expected error for %q", line)
+		}
+	}
+}
+
+func TestChecksumFileB2Sum(t *testing.T) {
+	var buf bytes.Buffer
+	for _, v := range b2sumLines {
+		buf.WriteString(v.line + "\n")
+	}
+	parsed, err := ReadChecksumFile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range b2sumLines[1:] {
+		if expected := HashAll([]byte(v.input), v.size); !bytes.Equal(parsed[v.filename], expected) {
+			t.Errorf("bad digest for %q: expected=%X, actual=%X", v.filename, expected, parsed[v.filename])
+		}
+	}
+
+	buf.Reset()
+	entries := map[string][]byte{b2sumLines[2].filename: HashAll([]byte(b2sumLines[2].input), 16)}
+	if err := WriteChecksumFile(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := buf.String(), b2sumLines[2].line+"\n"; actual != expected {
+		t.Errorf("bad checksum file: expected=%q, actual=%q", expected, actual)
+	}
+}
+
+func TestChecksumFileEscaping(t *testing.T) {
+	entries := map[string][]byte{
+		"new\nline":      HashAll([]byte("1"), 32),
+		`back\slash`:     HashAll([]byte("2"), 32),
+		`\n`:             HashAll([]byte("3"), 32),
+		"both\\\n":       HashAll([]byte("4"), 32),
+		"two  spaces":    HashAll([]byte("5"), 32),
+		"plain.txt":      HashAll([]byte("6"), 32),
+		"trailing\n\n\n": HashAll([]byte("7"), 32),
+	}
+	var buf bytes.Buffer
+	if err := WriteChecksumFile(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(entries) {
+		t.Errorf("bad line count: expected=%d, actual=%d\n%s", len(entries), lines, buf.String())
+	}
+	parsed, err := ReadChecksumFile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(entries) {
+		t.Fatalf("bad entry count: expected=%d, actual=%d", len(entries), len(parsed))
+	}
+	for name, digest := range entries {
+		if !bytes.Equal(parsed[name], digest) {
+			t.Errorf("bad digest for %q: expected=%X, actual=%X", name, digest, parsed[name])
+		}
+	}
+}
+
+func TestWriteChecksumFileEmpty(t *testing.T) {
+	for _, entries := range []map[string][]byte{
+		{"": HashAll(nil, 32)},
+		{"a": HashAll(nil, 32), "b": nil},
+	} {
+		var buf bytes.Buffer
+		if err := WriteChecksumFile(&buf, entries); err == nil {
+			t.Errorf("expected error for %q", entries)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("partial checksum file written: %q", buf.String())
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package blake2b
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// ErrChunkCount is returned by a ChunkVerifier that receives more or fewer
+// chunks than it has digests for.
+var ErrChunkCount = errors.New("blake2b: wrong number of chunks")
+
+// A ChunkError reports a chunk that does not match its expected digest.
+type ChunkError struct {
+	Index int
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("blake2b: chunk %d does not match its digest", e.Index)
+}
+
+// A ChunkVerifier checks data that arrives in chunks, such as a resumable
+// download, against a list of expected per-chunk digests.
+type ChunkVerifier struct {
+	expected [][]byte
+	next     int
+	err      error
+}
+
+// NewChunkVerifier returns a ChunkVerifier for the given digests. The
+// length of each digest selects the size of the checksum of its chunk.
+func NewChunkVerifier(expected [][]byte) *ChunkVerifier {
+	return &ChunkVerifier{expected: expected}
+}
+
+// Write verifies chunk as the next chunk of the stream and returns a
+// *ChunkError if it does not match. Once verification failed, every
+// further call returns the same error.
+func (v *ChunkVerifier) Write(chunk []byte) (int, error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	if v.next == len(v.expected) {
+		v.err = ErrChunkCount
+		return 0, v.err
+	}
+	expected := v.expected[v.next]
+	h, err := NewWith(WithSize(len(expected)))
+	if err != nil {
+		v.err = err
+		return 0, err
+	}
+	h.Write(chunk)
+	if subtle.ConstantTimeCompare(h.Sum(nil), expected) != 1 {
+		v.err = &ChunkError{v.next}
+		return 0, v.err
+	}
+	v.next++
+	return len(chunk), nil
+}
+
+// Close returns ErrChunkCount if fewer chunks were written than expected,
+// or the error of a failed Write.
+func (v *ChunkVerifier) Close() error {
+	if v.err == nil && v.next != len(v.expected) {
+		v.err = ErrChunkCount
+	}
+	return v.err
+}
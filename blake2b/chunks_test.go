@@ -0,0 +1,57 @@
+package blake2b
+
+import "testing"
+
+func chunkDigests(chunks []string) [][]byte {
+	digests := make([][]byte, len(chunks))
+	for i, chunk := range chunks {
+		digests[i] = HashAll([]byte(chunk), 32)
+	}
+	return digests
+}
+
+func TestChunkVerifier(t *testing.T) {
+	chunks := []string{"first chunk", "second chunk", "third chunk"}
+	v := NewChunkVerifier(chunkDigests(chunks))
+	for _, chunk := range chunks {
+		if n, err := v.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("Write(%q): n=%d, err=%v", chunk, n, err)
+		}
+	}
+	if err := v.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChunkVerifierCorrupted(t *testing.T) {
+	chunks := []string{"first chunk", "second chunk", "third chunk"}
+	v := NewChunkVerifier(chunkDigests(chunks))
+	v.Write([]byte(chunks[0]))
+	_, err := v.Write([]byte("second chunK"))
+	if e, ok := err.(*ChunkError); !ok || e.Index != 1 {
+		t.Fatalf("expected ChunkError for chunk 1, got %v", err)
+	}
+	if _, err := v.Write([]byte(chunks[2])); err == nil {
+		t.Error("Write after a mismatch succeeded")
+	}
+	if err := v.Close(); err == nil {
+		t.Error("Close after a mismatch succeeded")
+	}
+}
+
+func TestChunkVerifierCount(t *testing.T) {
+	chunks := []string{"first chunk", "second chunk"}
+
+	v := NewChunkVerifier(chunkDigests(chunks))
+	v.Write([]byte(chunks[0]))
+	if err := v.Close(); err != ErrChunkCount {
+		t.Errorf("too few chunks: expected ErrChunkCount, got %v", err)
+	}
+
+	v = NewChunkVerifier(chunkDigests(chunks))
+	v.Write([]byte(chunks[0]))
+	v.Write([]byte(chunks[1]))
+	if _, err := v.Write([]byte("extra")); err != ErrChunkCount {
+		t.Errorf("too many chunks: expected ErrChunkCount, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package blake2b
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+)
+
+// contentIDEncoding is the lowercase, unpadded base32 alphabet of RFC 4648.
+var contentIDEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// ContentID returns the 256-bit Blake2b checksum of data as a 52
+// character lowercase base32 string without padding. The id only uses
+// letters and digits, so it is safe as a file name on case-insensitive
+// file systems and as a storage key.
+func ContentID(data []byte) string {
+	sum := HashAll(data, 32)
+	return contentIDEncoding.EncodeToString(sum)
+}
+
+// SumBase64URL returns the size byte Blake2b checksum of data in the
+// unpadded base64url encoding of RFC 4648, as used in JWTs: a 32-byte
+// checksum encodes to 43 characters. It panics if size is not between 1
+// and Size.
+func SumBase64URL(data []byte, size int) string {
+	return base64.RawURLEncoding.EncodeToString(HashAll(data, size))
+}
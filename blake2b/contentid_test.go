@@ -0,0 +1,55 @@
+package blake2b
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestContentID(t *testing.T) {
+	id := ContentID([]byte("hello world"))
+	expected := "evwihmuxcfgsagzqc6pt6dxqzlhjpa3cfwszoqzgwq3bpcxo6yia"
+	if id != expected {
+		t.Errorf("bad id: expected=%s, actual=%s", expected, id)
+	}
+	if again := ContentID([]byte("hello world")); again != id {
+		t.Errorf("unstable id: expected=%s, actual=%s", id, again)
+	}
+	if other := ContentID([]byte("hello world!")); other == id {
+		t.Errorf("different inputs share the id %s", id)
+	}
+}
+
+func TestContentIDDecode(t *testing.T) {
+	for _, input := range [][]byte{nil, []byte("abc"), bytes.Repeat([]byte{0xff}, 1000)} {
+		id := ContentID(input)
+		raw, err := contentIDEncoding.DecodeString(id)
+		if err != nil {
+			t.Fatalf("decode %q: %v", id, err)
+		}
+		if expected := HashAll(input, 32); !bytes.Equal(raw, expected) {
+			t.Errorf("bad decoded id: expected=%X, actual=%X", expected, raw)
+		}
+	}
+}
+
+func TestSumBase64URL(t *testing.T) {
+	input := bytes.Repeat([]byte{0xfb, 0xff}, 100)
+	for _, size := range []int{1, 16, 20, 32, 33, Size} {
+		s := SumBase64URL(input, size)
+		if expected := (size*8 + 5) / 6; len(s) != expected {
+			t.Errorf("bad length (%d): expected=%d, actual=%d", size, expected, len(s))
+		}
+		raw, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatalf("decode %q: %v", s, err)
+		}
+		if expected := HashAll(input, size); !bytes.Equal(raw, expected) {
+			t.Errorf("bad decoded hash (%d): expected=%X, actual=%X", size, expected, raw)
+		}
+		if strings.ContainsAny(s, "+/=") {
+			t.Errorf("not unpadded base64url: %s", s)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package blake2b
+
+import "sync"
+
+// A Deduper detects duplicate blobs by their 256-bit Blake2b checksums.
+// It keeps one checksum per distinct blob, so memory grows with the
+// number of distinct blobs, not their size. Two different blobs are
+// taken as duplicates only if their checksums collide. The zero value is
+// ready to use. A Deduper is not safe for concurrent use; see
+// SyncDeduper.
+type Deduper struct {
+	seen map[Digest256]struct{}
+}
+
+// Add records data and reports whether it was not seen before.
+func (d *Deduper) Add(data []byte) (isNew bool) {
+	return d.addSum(Sum256(data))
+}
+
+func (d *Deduper) addSum(sum Digest256) bool {
+	if _, ok := d.seen[sum]; ok {
+		return false
+	}
+	if d.seen == nil {
+		d.seen = make(map[Digest256]struct{})
+	}
+	d.seen[sum] = struct{}{}
+	return true
+}
+
+// Len returns the number of distinct blobs added.
+func (d *Deduper) Len() int {
+	return len(d.seen)
+}
+
+// A SyncDeduper is a Deduper that is safe for concurrent use. Blobs are
+// hashed outside the lock, so concurrent Adds only serialize on the set
+// lookup.
+type SyncDeduper struct {
+	mu sync.Mutex
+	d  Deduper
+}
+
+// Add records data and reports whether it was not seen before. Of
+// concurrent Adds of the same blob, exactly one reports it as new.
+func (s *SyncDeduper) Add(data []byte) (isNew bool) {
+	sum := Sum256(data)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.addSum(sum)
+}
+
+// Len returns the number of distinct blobs added.
+func (s *SyncDeduper) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.Len()
+}
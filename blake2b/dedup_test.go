@@ -0,0 +1,52 @@
+package blake2b
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeduper(t *testing.T) {
+	var d Deduper
+	for _, v := range []struct {
+		blob  string
+		isNew bool
+	}{
+		{"a", true},
+		{"b", true},
+		{"a", false},
+		{"", true},
+		{"", false},
+		{"b", false},
+		{"ab", true},
+	} {
+		if actual := d.Add([]byte(v.blob)); actual != v.isNew {
+			t.Errorf("Add(%q): expected=%v, actual=%v", v.blob, v.isNew, actual)
+		}
+	}
+	if d.Len() != 4 {
+		t.Errorf("bad count: expected=4, actual=%d", d.Len())
+	}
+}
+
+func TestSyncDeduper(t *testing.T) {
+	var d SyncDeduper
+	var unique int64
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if d.Add([]byte(fmt.Sprint("blob", i))) {
+					atomic.AddInt64(&unique, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if unique != 100 || d.Len() != 100 {
+		t.Errorf("bad count: new=%d, len=%d, expected 100", unique, d.Len())
+	}
+}
@@ -0,0 +1,45 @@
+package blake2b
+
+import "crypto/subtle"
+
+// Fixed-size checksums, as returned by Sum128 through Sum512. Comparing
+// two of them with == takes time that depends on the position of the
+// first differing byte; use Equal for MACs and other secret values.
+type (
+	Digest128 [16]byte
+	Digest160 [20]byte
+	Digest224 [28]byte
+	Digest256 [32]byte
+	Digest384 [48]byte
+	Digest512 [64]byte
+)
+
+// Equal reports whether d and other are equal, in constant time.
+func (d Digest128) Equal(other Digest128) bool {
+	return subtle.ConstantTimeCompare(d[:], other[:]) == 1
+}
+
+// Equal reports whether d and other are equal, in constant time.
+func (d Digest160) Equal(other Digest160) bool {
+	return subtle.ConstantTimeCompare(d[:], other[:]) == 1
+}
+
+// Equal reports whether d and other are equal, in constant time.
+func (d Digest224) Equal(other Digest224) bool {
+	return subtle.ConstantTimeCompare(d[:], other[:]) == 1
+}
+
+// Equal reports whether d and other are equal, in constant time.
+func (d Digest256) Equal(other Digest256) bool {
+	return subtle.ConstantTimeCompare(d[:], other[:]) == 1
+}
+
+// Equal reports whether d and other are equal, in constant time.
+func (d Digest384) Equal(other Digest384) bool {
+	return subtle.ConstantTimeCompare(d[:], other[:]) == 1
+}
+
+// Equal reports whether d and other are equal, in constant time.
+func (d Digest512) Equal(other Digest512) bool {
+	return subtle.ConstantTimeCompare(d[:], other[:]) == 1
+}
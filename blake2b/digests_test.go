@@ -0,0 +1,40 @@
+package blake2b
+
+import "testing"
+
+// The Equal methods delegate to subtle.ConstantTimeCompare, which
+// examines every byte; these tests check their results.
+func TestDigestEqual(t *testing.T) {
+	a, b := []byte("message a"), []byte("message b")
+	for _, v := range []struct {
+		name            string
+		same, different bool
+	}{
+		{"Digest128", Sum128(a).Equal(Sum128(a)), Sum128(a).Equal(Sum128(b))},
+		{"Digest160", Sum160(a).Equal(Sum160(a)), Sum160(a).Equal(Sum160(b))},
+		{"Digest224", Sum224(a).Equal(Sum224(a)), Sum224(a).Equal(Sum224(b))},
+		{"Digest256", Sum256(a).Equal(Sum256(a)), Sum256(a).Equal(Sum256(b))},
+		{"Digest384", Sum384(a).Equal(Sum384(a)), Sum384(a).Equal(Sum384(b))},
+		{"Digest512", Sum512(a).Equal(Sum512(a)), Sum512(a).Equal(Sum512(b))},
+	} {
+		if !v.same {
+			t.Errorf("%s: equal digests compare unequal", v.name)
+		}
+		if v.different {
+			t.Errorf("%s: different digests compare equal", v.name)
+		}
+	}
+}
+
+func TestDigestEqualLastByte(t *testing.T) {
+	d := Sum256([]byte("message"))
+	other := d
+	other[len(other)-1] ^= 1
+	if d.Equal(other) {
+		t.Error("digests differing in the last byte compare equal")
+	}
+	var raw [32]byte = d
+	if !Digest256(raw).Equal(d) {
+		t.Error("converted array compares unequal")
+	}
+}
@@ -0,0 +1,63 @@
+package blake2b
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+type edgeCase struct {
+	Comment  string
+	Key      string
+	Salt     string
+	Personal string
+	Input    string
+	Size     int
+	Output   string
+	Result   string
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestEdgeCases runs the cases in testdata/edge.json. Cases with result
+// "invalid" must be rejected by NewWith.
+func TestEdgeCases(t *testing.T) {
+	data, err := os.ReadFile("testdata/edge.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []edgeCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range cases {
+		h, err := NewWith(
+			WithKey(decodeHex(t, c.Key)),
+			WithSalt(decodeHex(t, c.Salt)),
+			WithPersonal(decodeHex(t, c.Personal)),
+			WithSize(c.Size),
+		)
+		if c.Result == "invalid" {
+			if err == nil {
+				t.Errorf("%s: expected error", c.Comment)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.Comment, err)
+			continue
+		}
+		h.Write(decodeHex(t, c.Input))
+		if actual := hex.EncodeToString(h.Sum(nil)); actual != c.Output {
+			t.Errorf("%s: expected=%s, actual=%s", c.Comment, c.Output, actual)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package blake2b
+
+import (
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// SumFilesP returns the size byte Blake2bp checksums of the files at
+// paths, keyed by path. The files are hashed by a pool of GOMAXPROCS
+// goroutines. If some files cannot be read, the map holds the checksums
+// of the others and the error is the one of the first such file in
+// paths.
+func SumFilesP(paths []string, size int) (map[string][]byte, error) {
+	if size < 1 || size > Size {
+		return nil, errors.New("blake2b: invalid digest size")
+	}
+	sums := make([][]byte, len(paths))
+	errs := make([]error, len(paths))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				sums[i], errs[i] = sumFileP(paths[i], size)
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := make(map[string][]byte, len(paths))
+	var first error
+	for i, path := range paths {
+		if errs[i] != nil {
+			if first == nil {
+				first = errs[i]
+			}
+			continue
+		}
+		result[path] = sums[i]
+	}
+	return result, first
+}
+
+// sumFileP returns the size byte Blake2bp checksum of the file at path.
+func sumFileP(path string, size int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := NewPSized(size, nil)
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,63 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Run with -race to check the worker pool.
+func TestSumFilesP(t *testing.T) {
+	dir := t.TempDir()
+	var contents [][]byte
+	var names []string
+	for i := 0; i < 20; i++ {
+		data := bytes.Repeat([]byte{byte(i)}, i*1000)
+		name := filepath.Join(dir, fmt.Sprintf("file%d", i))
+		if err := os.WriteFile(name, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, data)
+		names = append(names, name)
+	}
+
+	sums, err := SumFilesP(names, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sums) != len(names) {
+		t.Fatalf("bad checksum count: %d", len(sums))
+	}
+	for i, name := range names {
+		h := NewPSized(32, nil)
+		h.Write(contents[i])
+		if expected := h.Sum(nil); !bytes.Equal(sums[name], expected) {
+			t.Errorf("bad checksum of %s: expected=%X, actual=%X", name, expected, sums[name])
+		}
+	}
+}
+
+func TestSumFilesPMissing(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present")
+	if err := os.WriteFile(present, []byte("abc"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	sums, err := SumFilesP([]string{present, missing, filepath.Join(dir, "also missing")}, Size)
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+	if pe, ok := err.(*os.PathError); !ok || pe.Path != missing {
+		t.Errorf("error is not the one of the first missing file: %v", err)
+	}
+	if _, ok := sums[present]; !ok || len(sums) != 1 {
+		t.Errorf("bad checksums: %X", sums)
+	}
+	if _, err := SumFilesP(nil, 0); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
@@ -0,0 +1,74 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func framedTag(ad, msg string) []byte {
+	m := NewFramedMAC([]byte("secret key"), 32)
+	m.WriteAD([]byte(ad))
+	m.WriteMessage([]byte(msg))
+	return m.Sum(nil)
+}
+
+func TestFramedMAC(t *testing.T) {
+	actual := fmt.Sprintf("%X", framedTag("header", "message"))
+	expected := "E1EEEA51EE36BC15B7FC3EEBAD457B5D70C8240577F6CAC1AFE8FD6EB576BC6D"
+	if actual != expected {
+		t.Errorf("bad tag: expected=%s, actual=%s", expected, actual)
+	}
+	if bytes.Equal(framedTag("header", "message"), MAC([]byte("secret key"), []byte("headermessage"), 32)) {
+		t.Error("framed tag equals the plain MAC of the concatenation")
+	}
+}
+
+func TestFramedMACBoundary(t *testing.T) {
+	tags := [][]byte{
+		framedTag("", "headermessage"),
+		framedTag("h", "eadermessage"),
+		framedTag("header", "message"),
+		framedTag("headermessag", "e"),
+		framedTag("headermessage", ""),
+	}
+	for i := range tags {
+		for j := i + 1; j < len(tags); j++ {
+			if bytes.Equal(tags[i], tags[j]) {
+				t.Errorf("tags %d and %d are equal", i, j)
+			}
+		}
+	}
+}
+
+func TestFramedMACStreaming(t *testing.T) {
+	m := NewFramedMAC([]byte("secret key"), 32)
+	m.WriteAD([]byte("hea"))
+	m.WriteAD([]byte("der"))
+	m.WriteMessage([]byte("mess"))
+	first := m.Sum(nil)
+	m.WriteMessage([]byte("age"))
+	if expected := framedTag("header", "message"); !bytes.Equal(m.Sum(nil), expected) {
+		t.Errorf("bad streamed tag: expected=%X, actual=%X", expected, m.Sum(nil))
+	}
+	if expected := framedTag("header", "mess"); !bytes.Equal(first, expected) {
+		t.Errorf("bad intermediate tag: expected=%X, actual=%X", expected, first)
+	}
+	m.Reset()
+	m.WriteAD([]byte("header"))
+	m.WriteMessage([]byte("message"))
+	if expected := framedTag("header", "message"); !bytes.Equal(m.Sum(nil), expected) {
+		t.Errorf("bad tag after Reset: expected=%X, actual=%X", expected, m.Sum(nil))
+	}
+}
+
+func TestFramedMACADAfterMessage(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for associated data after message")
+		}
+	}()
+	m := NewFramedMAC([]byte("secret key"), 32)
+	m.WriteMessage([]byte("message"))
+	m.WriteAD([]byte("header"))
+}
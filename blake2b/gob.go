@@ -0,0 +1,21 @@
+package blake2b
+
+import "encoding/gob"
+
+// HashGob returns the Blake2b checksum of size bytes of the gob encoding
+// of v.
+//
+// The gob format is only deterministic within a single program run: map
+// entries are encoded in iteration order, and the encoding of types may
+// change between Go versions. Digests that must stay stable should hash a
+// length-prefixed encoding of the fields instead.
+func HashGob(v interface{}, size int) ([]byte, error) {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		return nil, err
+	}
+	if err := gob.NewEncoder(h).Encode(v); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
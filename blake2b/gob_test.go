@@ -0,0 +1,40 @@
+package blake2b
+
+import (
+	"bytes"
+	"testing"
+)
+
+type gobRecord struct {
+	Name  string
+	Count int
+	Tags  []string
+}
+
+func TestHashGob(t *testing.T) {
+	v := gobRecord{"one", 2, []string{"three"}}
+	first, err := HashGob(v, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 32 {
+		t.Errorf("bad digest length: %d", len(first))
+	}
+	second, _ := HashGob(v, 32)
+	if !bytes.Equal(first, second) {
+		t.Errorf("HashGob is not deterministic: first=%X, second=%X", first, second)
+	}
+	v.Count++
+	if changed, _ := HashGob(v, 32); bytes.Equal(first, changed) {
+		t.Error("HashGob did not change with the value")
+	}
+}
+
+func TestHashGobError(t *testing.T) {
+	if _, err := HashGob(func() {}, 32); err == nil {
+		t.Error("expected error for un-encodable value")
+	}
+	if _, err := HashGob(1, 0); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
@@ -0,0 +1,27 @@
+package blake2b
+
+import "sort"
+
+// HashMap returns the Blake2b checksum of size bytes of the entries of m.
+// The entries are hashed in key order, each key and value preceded by its
+// uvarint length as with NewPrefixed, so the digest does not depend on
+// the iteration order of m. A nil value hashes like an empty one. It
+// panics if size is not between 1 and Size.
+func HashMap(m map[string][]byte, size int) []byte {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		panic(err)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	p := &prefixed{h.(*digest)}
+	for _, k := range keys {
+		p.Write([]byte(k))
+		p.Write(m[k])
+	}
+	return p.Sum(nil)
+}
@@ -0,0 +1,42 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestHashMap(t *testing.T) {
+	a := map[string][]byte{}
+	b := map[string][]byte{}
+	for i := 0; i < 100; i++ {
+		a[fmt.Sprint(i)] = []byte{byte(i)}
+		b[fmt.Sprint(99-i)] = []byte{byte(99 - i)}
+	}
+	sum := HashMap(a, 32)
+	if actual := HashMap(b, 32); !bytes.Equal(actual, sum) {
+		t.Errorf("insertion order changed the hash: expected=%X, actual=%X", sum, actual)
+	}
+	b["42"] = []byte{43}
+	if actual := HashMap(b, 32); bytes.Equal(actual, sum) {
+		t.Error("changed value did not change the hash")
+	}
+}
+
+func TestHashMapFraming(t *testing.T) {
+	for _, m := range []map[string][]byte{
+		{"ab": []byte("c")},
+		{"a": []byte("bc")},
+		{"a": nil, "b": []byte("c")},
+	} {
+		for _, other := range []map[string][]byte{{"abc": nil}, {"": []byte("abc")}} {
+			if bytes.Equal(HashMap(m, Size), HashMap(other, Size)) {
+				t.Errorf("maps %q and %q hash identically", m, other)
+			}
+		}
+	}
+	expected := "D8D264497594B925E00EB3F00EF9D49148DDCA329E662DF261985F7FE5F46F27"
+	if actual := fmt.Sprintf("%X", HashMap(map[string][]byte{"b": []byte("2"), "a": []byte("1")}, 32)); actual != expected {
+		t.Errorf("bad hash: expected=%s, actual=%s", expected, actual)
+	}
+}
@@ -0,0 +1,108 @@
+package blake2b
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+// WriteHexTo writes the lowercase hex encoding of the checksum of the data
+// to w. Like Sum, it does not change the underlying hash state.
+func (d *digest) WriteHexTo(w io.Writer) (int, error) {
+	d.checkInit()
+	dd := *d
+	sum := dd.checkSum()
+	var out [2 * Size]byte
+	hex.Encode(out[:], sum[:d.size])
+	return w.Write(out[:2*d.size])
+}
+
+// HexString returns the hex encoding of the checksum of the data, in
+// uppercase if upper is set. It does not change the underlying hash state.
+func (d *digest) HexString(upper bool) string {
+	s := hex.EncodeToString(d.Sum(make([]byte, 0, Size)))
+	if upper {
+		s = strings.ToUpper(s)
+	}
+	return s
+}
+
+// ConstantTimeHexEqual reports whether the hex strings a and b encode the
+// same digest, comparing the decoded bytes with
+// subtle.ConstantTimeCompare; upper and lower case digits are equal. It
+// returns false if either string is not valid hex or if the digests
+// differ in length; the lengths and validity of the inputs are not
+// treated as secret.
+func ConstantTimeHexEqual(a, b string) bool {
+	da, errA := hex.DecodeString(a)
+	db, errB := hex.DecodeString(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(da, db) == 1
+}
+
+// A HexHash is a Blake2b digest that implements fmt.Stringer: String
+// returns the lowercase hex checksum of the data written so far, so the
+// hasher can be passed to fmt.Println directly. The checksum is computed
+// only when String is called and, like Sum, does not change the state.
+//
+// The checksum of a keyed digest is a MAC, which should not end up in
+// logs by accident. String therefore panics for a keyed HexHash unless
+// RevealKeyed was called. A HexHash must be created with NewHexHash; the
+// zero value panics on use.
+type HexHash struct {
+	d           *digest
+	revealKeyed bool
+}
+
+// NewHexHash returns a new HexHash configured by the given options, as
+// with NewWith.
+func NewHexHash(opts ...Option) (*HexHash, error) {
+	h, err := NewWith(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &HexHash{d: h.(*digest)}, nil
+}
+
+func (h *HexHash) digest() *digest {
+	if h.d == nil {
+		panic("blake2b: use of HexHash not created by NewHexHash")
+	}
+	return h.d
+}
+
+// Write adds more data to the running hash. Like the Write of other
+// digests, it absorbs only the bytes up to a limit set with WithMaxInput
+// and returns ErrInputLimit together with their count.
+func (h *HexHash) Write(p []byte) (int, error) { return h.digest().Write(p) }
+
+// Sum appends the current checksum to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (h *HexHash) Sum(b []byte) []byte { return h.digest().Sum(b) }
+
+// Reset resets the hash to its initial state, keeping its configuration.
+func (h *HexHash) Reset() { h.digest().Reset() }
+
+// Size returns the number of bytes Sum returns.
+func (h *HexHash) Size() int { return h.digest().Size() }
+
+// BlockSize returns the hash's underlying block size.
+func (h *HexHash) BlockSize() int { return BlockSize }
+
+// RevealKeyed allows String to return the checksum of a keyed HexHash.
+func (h *HexHash) RevealKeyed() {
+	h.revealKeyed = true
+}
+
+// String returns the lowercase hex checksum of the data written so far.
+// It panics for a keyed HexHash unless RevealKeyed was called.
+func (h *HexHash) String() string {
+	d := h.digest()
+	if d.IsKeyed() && !h.revealKeyed {
+		panic("blake2b: String of keyed HexHash without RevealKeyed")
+	}
+	return d.HexString(false)
+}
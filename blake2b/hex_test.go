@@ -0,0 +1,142 @@
+package blake2b
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteHexTo(t *testing.T) {
+	for _, size := range []int{1, 32, Size} {
+		h, _ := NewWith(WithSize(size))
+		h.Write([]byte("one two three"))
+
+		var buf bytes.Buffer
+		n, err := h.(*digest).WriteHexTo(&buf)
+		if n != 2*size || err != nil {
+			t.Fatalf("WriteHexTo: n=%d, err=%v", n, err)
+		}
+		if expected := hex.EncodeToString(h.Sum(nil)); buf.String() != expected {
+			t.Errorf("bad hex (%d): expected=%s, actual=%s", size, expected, buf.String())
+		}
+	}
+}
+
+func TestHexString(t *testing.T) {
+	h := New()
+	h.Write([]byte("one two three"))
+	expected := hex.EncodeToString(h.Sum(nil))
+
+	d := h.(*digest)
+	if actual := d.HexString(false); actual != expected {
+		t.Errorf("bad lowercase hex: expected=%s, actual=%s", expected, actual)
+	}
+	if actual := d.HexString(true); actual != strings.ToUpper(expected) {
+		t.Errorf("bad uppercase hex: expected=%s, actual=%s", strings.ToUpper(expected), actual)
+	}
+}
+
+func TestConstantTimeHexEqual(t *testing.T) {
+	sum := hex.EncodeToString(HashAll([]byte("abc"), 32))
+	for _, v := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{sum, sum, true},
+		{sum, strings.ToUpper(sum), true},
+		{sum, hex.EncodeToString(HashAll([]byte("abd"), 32)), false},
+		{sum, sum[:32], false},
+		{sum, sum + "00", false},
+		{sum, sum[:63] + "g", false},
+		{sum[:63], sum[:63], false},
+	} {
+		if actual := ConstantTimeHexEqual(v.a, v.b); actual != v.expected {
+			t.Errorf("ConstantTimeHexEqual(%q, %q): expected=%v, actual=%v", v.a, v.b, v.expected, actual)
+		}
+	}
+}
+
+func TestHexHash(t *testing.T) {
+	h, err := NewHexHash(WithSize(32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var _ hash.Hash = h
+	io.WriteString(h, "one two three")
+	expected := hex.EncodeToString(HashAll([]byte("one two three"), 32))
+	if actual := fmt.Sprint(h); actual != expected {
+		t.Errorf("bad hash: expected=%s, actual=%s", expected, actual)
+	}
+	io.WriteString(h, " four")
+	expected = hex.EncodeToString(HashAll([]byte("one two three four"), 32))
+	if actual := h.String(); actual != expected {
+		t.Errorf("bad hash after more input: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestHexHashKeyed(t *testing.T) {
+	h, err := NewHexHash(WithKey([]byte("secret")), WithSize(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(h, "message")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for keyed String without RevealKeyed")
+			}
+		}()
+		_ = h.String()
+	}()
+
+	h.RevealKeyed()
+	expected := hex.EncodeToString(MAC([]byte("secret"), []byte("message"), 16))
+	if actual := h.String(); actual != expected {
+		t.Errorf("bad keyed hash: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestHexHashInputLimit(t *testing.T) {
+	h, err := NewHexHash(WithMaxInput(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := io.WriteString(h, "one two"); n != 4 || err != ErrInputLimit {
+		t.Fatalf("Write over limit: n=%d, err=%v", n, err)
+	}
+	expected := hex.EncodeToString(HashAll([]byte("one "), Size))
+	if actual := h.String(); actual != expected {
+		t.Errorf("bad hash: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestHexHashHidesDigest(t *testing.T) {
+	h, _ := NewHexHash(WithKey([]byte("secret")))
+	var x interface{} = h
+	if _, ok := x.(interface{ HexString(bool) string }); ok {
+		t.Error("HexHash exposes HexString, bypassing RevealKeyed")
+	}
+	if _, ok := x.(interface{ WriteHexTo(io.Writer) (int, error) }); ok {
+		t.Error("HexHash exposes WriteHexTo, bypassing RevealKeyed")
+	}
+}
+
+func TestHexHashZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for zero HexHash")
+		}
+	}()
+	var h HexHash
+	_ = h.String()
+}
+
+func TestNewHexHashInvalid(t *testing.T) {
+	if _, err := NewHexHash(WithSize(0)); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
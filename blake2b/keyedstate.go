@@ -0,0 +1,37 @@
+package blake2b
+
+import "hash"
+
+// A KeyedState is a snapshot of a digest taken right after its key block
+// was compressed. Digests started from it with NewFromKeyedState neither
+// rebuild the parameter block nor compress the key block, which saves a
+// compression for every MAC made under one key. Only the MAC of an empty
+// message compresses the key block again, since it is then the final
+// block.
+type KeyedState struct {
+	d digest
+}
+
+// KeyedState returns the state d is in right after Reset, that is with
+// its configuration applied and its key block compressed.
+func (d *digest) KeyedState() KeyedState {
+	d.checkInit()
+	s := KeyedState{d: d.clone()}
+	s.d.rbuf = nil
+	s.d.Reset()
+	if s.d.buflen > 0 {
+		s.d.incrementCounter(BlockSize)
+		compress(&s.d.h, s.d.stage(), s.d.t, false, false)
+		s.d.buflen = 0
+	}
+	return s
+}
+
+// NewFromKeyedState returns a new hash.Hash starting from the state s.
+// Resetting it absorbs the key again, as for any digest. It panics if s
+// was not obtained from a digest.
+func NewFromKeyedState(s KeyedState) hash.Hash {
+	s.d.checkInit()
+	d := s.d.clone()
+	return &d
+}
@@ -0,0 +1,67 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestKeyedState(t *testing.T) {
+	key := make([]byte, 64)
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	keyed := NewKeyed(key)
+	keyed.Write([]byte("ignored"))
+	state := keyed.(*digest).KeyedState()
+
+	for len, expected := range keyed2B {
+		input := make([]byte, len)
+		for i := 0; i < len; i++ {
+			input[i] = byte(i)
+		}
+
+		h := NewFromKeyedState(state)
+		h.Write(input)
+		actual := fmt.Sprintf("%X", h.Sum(nil))
+
+		if actual != expected {
+			t.Fatalf("bad hash (%d): expected=%s, actual=%s", len, expected, actual)
+		}
+	}
+}
+
+// TestKeyedStateCompressed checks that the key block is compressed once,
+// by KeyedState, and not again by the digests started from it.
+func TestKeyedStateCompressed(t *testing.T) {
+	key := []byte("key")
+	state := NewKeyed(key).(*digest).KeyedState()
+	d := NewFromKeyedState(state).(*digest)
+	if d.t != [2]uint64{BlockSize, 0} || d.buflen != 0 {
+		t.Fatalf("key block not compressed: t=%v, buflen=%d", d.t, d.buflen)
+	}
+
+	for _, message := range []string{"", "m", string(make([]byte, 3*BlockSize))} {
+		h := NewFromKeyedState(state)
+		h.Write([]byte(message))
+		expected := MAC(key, []byte(message), Size)
+		if actual := h.Sum(nil); !bytes.Equal(actual, expected) {
+			t.Errorf("bad hash (%d): expected=%X, actual=%X", len(message), expected, actual)
+		}
+		h.Reset()
+		h.Write([]byte(message))
+		if actual := h.Sum(nil); !bytes.Equal(actual, expected) {
+			t.Errorf("bad hash after Reset (%d): expected=%X, actual=%X", len(message), expected, actual)
+		}
+	}
+}
+
+func TestKeyedStateZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for zero KeyedState")
+		}
+	}()
+	NewFromKeyedState(KeyedState{})
+}
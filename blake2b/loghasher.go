@@ -0,0 +1,37 @@
+package blake2b
+
+// A LogHasher computes running checksums of an append-only log. Each
+// entry is absorbed preceded by its uvarint length as with NewPrefixed,
+// so the checksum identifies the sequence of entries and not just their
+// concatenation. A LogHasher is not safe for concurrent use.
+type LogHasher struct {
+	p       prefixed
+	entries int
+}
+
+// NewLogHasher returns a LogHasher computing Blake2b checksums of size
+// bytes. It panics if size is not between 1 and Size.
+func NewLogHasher(size int) *LogHasher {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		panic(err)
+	}
+	return &LogHasher{p: prefixed{h.(*digest)}}
+}
+
+// Append absorbs entry as the next entry of the log.
+func (l *LogHasher) Append(entry []byte) {
+	l.p.Write(entry)
+	l.entries++
+}
+
+// Digest returns the checksum of the entries appended so far. It does
+// not change the state, so appending can continue.
+func (l *LogHasher) Digest() []byte {
+	return l.p.Sum(nil)
+}
+
+// Len returns the number of entries appended so far.
+func (l *LogHasher) Len() int {
+	return l.entries
+}
@@ -0,0 +1,44 @@
+package blake2b
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+func TestLogHasher(t *testing.T) {
+	l := NewLogHasher(32)
+	var framed []byte
+	for i := 0; i < 300; i++ {
+		entry := bytes.Repeat([]byte(fmt.Sprint(i)), i)
+		l.Append(entry)
+		framed = binary.AppendUvarint(framed, uint64(len(entry)))
+		framed = append(framed, entry...)
+
+		if i%50 == 0 || i == 299 {
+			if expected := HashAll(framed, 32); !bytes.Equal(l.Digest(), expected) {
+				t.Fatalf("bad digest after %d entries: expected=%X, actual=%X", i+1, expected, l.Digest())
+			}
+		}
+	}
+	if l.Len() != 300 {
+		t.Errorf("bad entry count: %d", l.Len())
+	}
+}
+
+func TestLogHasherEntries(t *testing.T) {
+	a := NewLogHasher(32)
+	a.Append([]byte("ab"))
+	b := NewLogHasher(32)
+	b.Append([]byte("a"))
+	b.Append([]byte("b"))
+	if bytes.Equal(a.Digest(), b.Digest()) {
+		t.Error("different entry boundaries give the same digest")
+	}
+	before := b.Digest()
+	b.Append(nil)
+	if bytes.Equal(b.Digest(), before) {
+		t.Error("empty entry did not change the digest")
+	}
+}
@@ -0,0 +1,64 @@
+package blake2b
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// MAC returns a size byte keyed Blake2b tag of msg.
+//
+// The tag size is part of the parameter block, so a 16-byte tag is not
+// the prefix of a 32-byte tag under the same key: a tag truncated by an
+// attacker cannot pass for a shorter configured one. It panics if size is
+// not between 1 and Size or if key is longer than KeySize.
+func MAC(key, msg []byte, size int) []byte {
+	if len(msg) <= BlockSize && len(key) <= KeySize && size >= 1 && size <= Size {
+		sum := sumShort(key, msg, size)
+		return append([]byte(nil), sum[:size]...)
+	}
+	h, err := NewWith(WithKey(key), WithSize(size))
+	if err != nil {
+		panic(err)
+	}
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// SelectMAC returns a copy of tag a if cond is 1 and of tag b if cond is
+// 0, in constant time: the selection is done with
+// subtle.ConstantTimeSelect over every byte, without branching on cond.
+// The result is undefined for other values of cond. It panics if a and b
+// differ in length; tag lengths are not secret.
+func SelectMAC(cond int, a, b []byte) []byte {
+	if len(a) != len(b) {
+		panic("blake2b: tags of different lengths")
+	}
+	out := make([]byte, len(a))
+	for i := range out {
+		out[i] = byte(subtle.ConstantTimeSelect(cond, int(a[i]), int(b[i])))
+	}
+	return out
+}
+
+// VerifyBatch checks each tags[i] against the MAC of messages[i] under
+// key, with the tag size given by the length of tags[i]. Every item is
+// verified in full and compared with subtle.ConstantTimeCompare, so the
+// time taken does not reveal which items failed. A tag of invalid length
+// fails. It returns an error if key is longer than KeySize or if
+// messages and tags differ in length.
+func VerifyBatch(key []byte, messages, tags [][]byte) ([]bool, error) {
+	if len(key) > KeySize {
+		return nil, errors.New("blake2b: invalid key size")
+	}
+	if len(messages) != len(tags) {
+		return nil, errors.New("blake2b: messages and tags differ in number")
+	}
+	ok := make([]bool, len(tags))
+	for i, tag := range tags {
+		if len(tag) < 1 || len(tag) > Size {
+			continue
+		}
+		ok[i] = subtle.ConstantTimeCompare(MAC(key, messages[i], len(tag)), tag) == 1
+	}
+	return ok, nil
+}
@@ -0,0 +1,77 @@
+package blake2b
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestMAC(t *testing.T) {
+	key := []byte("secret key")
+	msg := []byte("message")
+
+	tag16 := MAC(key, msg, 16)
+	expected := "db5104a95c3d7a3e12dcdd0d07b445d8"
+	if actual := hex.EncodeToString(tag16); actual != expected {
+		t.Errorf("bad tag: expected=%s, actual=%s", expected, actual)
+	}
+	tag32 := MAC(key, msg, 32)
+	if len(tag16) != 16 || len(tag32) != 32 {
+		t.Fatalf("bad tag lengths: %d, %d", len(tag16), len(tag32))
+	}
+	if bytes.Equal(tag16, tag32[:16]) {
+		t.Error("16-byte tag is a truncation of the 32-byte tag")
+	}
+}
+
+func TestSelectMAC(t *testing.T) {
+	a := MAC([]byte("key a"), []byte("message"), 32)
+	b := MAC([]byte("key b"), []byte("message"), 32)
+	if actual := SelectMAC(1, a, b); !bytes.Equal(actual, a) {
+		t.Errorf("bad selection for 1: expected=%X, actual=%X", a, actual)
+	}
+	if actual := SelectMAC(0, a, b); !bytes.Equal(actual, b) {
+		t.Errorf("bad selection for 0: expected=%X, actual=%X", b, actual)
+	}
+	if actual := SelectMAC(1, a, b); &actual[0] == &a[0] {
+		t.Error("SelectMAC returned its argument instead of a copy")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for tags of different lengths")
+		}
+	}()
+	SelectMAC(1, a, b[:16])
+}
+
+func TestVerifyBatch(t *testing.T) {
+	key := []byte("secret key")
+	messages := [][]byte{[]byte("a"), []byte("b"), bytes.Repeat([]byte("c"), 300), []byte("d"), []byte("e")}
+	tags := [][]byte{
+		MAC(key, messages[0], 32),
+		MAC(key, messages[0], 32),
+		MAC(key, messages[2], 16),
+		MAC([]byte("other key"), messages[3], 32),
+		nil,
+	}
+	ok, err := VerifyBatch(key, messages, tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []bool{true, false, true, false, false}
+	for i := range expected {
+		if ok[i] != expected[i] {
+			t.Errorf("bad result (%d): expected=%v, actual=%v", i, expected[i], ok[i])
+		}
+	}
+}
+
+func TestVerifyBatchInvalid(t *testing.T) {
+	if _, err := VerifyBatch(nil, make([][]byte, 2), make([][]byte, 3)); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+	if _, err := VerifyBatch(make([]byte, KeySize+1), nil, nil); err == nil {
+		t.Error("expected error for oversized key")
+	}
+}
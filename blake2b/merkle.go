@@ -0,0 +1,45 @@
+package blake2b
+
+// Domain separation prefixes of MerkleRoot, as in RFC 6962.
+const (
+	merkleLeaf = 0x00
+	merkleNode = 0x01
+)
+
+// MerkleRoot returns the root of a binary Merkle tree with Blake2b nodes
+// over leaves, in order.
+//
+// A leaf hashes as Blake2b(0x00 || leaf) and an inner node as
+// Blake2b(0x01 || left || right), all with Size byte digests. The
+// distinct prefixes keep a leaf from passing for an inner node, which
+// would otherwise allow second preimages. When a level has an odd number
+// of nodes, the last one is promoted to the next level unchanged rather
+// than paired with itself. The root of no leaves is the Blake2b digest
+// of the empty input.
+func MerkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return HashAll(nil, Size)
+	}
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		h := New()
+		h.Write([]byte{merkleLeaf})
+		h.Write(leaf)
+		level[i] = h.Sum(nil)
+	}
+	for len(level) > 1 {
+		next := level[:0]
+		for i := 0; i+1 < len(level); i += 2 {
+			h := New()
+			h.Write([]byte{merkleNode})
+			h.Write(level[i])
+			h.Write(level[i+1])
+			next = append(next, h.Sum(nil))
+		}
+		if len(level)%2 == 1 {
+			next = append(next, level[len(level)-1])
+		}
+		level = next
+	}
+	return level[0]
+}
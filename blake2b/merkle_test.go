@@ -0,0 +1,41 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	leaves := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}
+	for n, expected := range []string{
+		"786A02F742015903C6C6FD852552D272912F4740E15847618A86E217F71F5419D25E1031AFEE585313896444934EB04B903A685B1448B755D56F701AFE9BE2CE",
+		"27FD241F10A0145111C137D684025960DF4A37F82769B976C43A918BEA676AB939C65A19DAC3796282D42F6B9C08FB534DC432A948084F8764608CC259BD75EE",
+		"B90869844959BD2F5BBC5C786558FD1E2CAD6D7D0017D3124FEF8204FFA2281A8644A8DFF5FA3DB1C296661195032CDA62F5551AE7ED7FC78D7096946150867A",
+		"56691A8FCC2E8B2C2C2E34BCA42B9E0A5CB5E16BABB914506E40A5932E86D402D6F5C07BB6338A3D3B6931167F21E546B54F8560820F25706E70C7491BE1C9F9",
+		"AC6EDE08DB2C1A6A88C716820FD72F5FA339F625D67F04353E6A8ADC0310D0887BA25FE9FCA6BAA38E5A24C24ABB47308A8B2EA985FB9DD25D5B7F3A70351732",
+	} {
+		if actual := fmt.Sprintf("%X", MerkleRoot(leaves[:n])); actual != expected {
+			t.Errorf("bad root (%d leaves): expected=%s, actual=%s", n, expected, actual)
+		}
+	}
+}
+
+func TestMerkleRootSecondPreimage(t *testing.T) {
+	leaves := [][]byte{[]byte("a"), []byte("b")}
+	root := MerkleRoot(leaves)
+
+	// Hashing the concatenated leaf digests as a single leaf must not
+	// reproduce the root.
+	h := New()
+	h.Write([]byte{merkleLeaf})
+	h.Write([]byte("a"))
+	forged := h.Sum(nil)
+	h.Reset()
+	h.Write([]byte{merkleLeaf})
+	h.Write([]byte("b"))
+	forged = h.Sum(forged)
+	if bytes.Equal(MerkleRoot([][]byte{forged}), root) {
+		t.Error("inner node passed for a leaf")
+	}
+}
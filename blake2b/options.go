@@ -0,0 +1,136 @@
+package blake2b
+
+import (
+	"errors"
+	"hash"
+)
+
+// ErrInputLimit is returned by Write when the input limit configured
+// with WithMaxInput is exceeded.
+var ErrInputLimit = errors.New("blake2b: input limit exceeded")
+
+// An Option configures a digest returned by NewWith.
+type Option func(*digest) error
+
+// NewWith returns a new hash.Hash computing the Blake2b checksum,
+// configured by the given options.
+func NewWith(opts ...Option) (hash.Hash, error) {
+	d := newDigest()
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+	d.Reset()
+	return d, nil
+}
+
+// WithMaxInput limits the number of bytes that may be written between
+// two calls to Reset. Bytes past the limit are not absorbed.
+func WithMaxInput(n uint64) Option {
+	return func(d *digest) error {
+		d.limited = true
+		d.maxInput = n
+		return nil
+	}
+}
+
+// maxBufferBlocks bounds the staging buffer of WithBufferBlocks to 8 MiB.
+const maxBufferBlocks = 1 << 16
+
+// WithBufferBlocks sizes the staging buffer of the digest to n blocks,
+// between 1 and 65536; the default is 2. Write then compresses the
+// buffered blocks in batches of n, which can suit workloads of many small
+// writes. The buffer costs n*BlockSize bytes per digest. The digest is
+// the same for any n.
+func WithBufferBlocks(n int) Option {
+	return func(d *digest) error {
+		if n < 1 || n > maxBufferBlocks {
+			return errors.New("blake2b: invalid buffer size")
+		}
+		d.ext = nil
+		if n != len(d.buf)/BlockSize {
+			d.ext = make([]byte, n*BlockSize)
+		}
+		return nil
+	}
+}
+
+// WithBigEndianOutput serializes the state words big-endian in the
+// checksum, byte-swapping each 8-byte word of the standard output before
+// truncation to the digest size. This is not Blake2b as specified, and
+// only meant to interoperate with legacy systems storing the words in
+// that order.
+func WithBigEndianOutput() Option {
+	return func(d *digest) error {
+		d.bigEndian = true
+		return nil
+	}
+}
+
+// WithLastNode sets the last node flag, which finalization applies in
+// addition to the last block flag. Sequential hashing leaves it unset;
+// some implementations set it for a lone node even outside tree mode.
+func WithLastNode(last bool) Option {
+	return func(d *digest) error {
+		d.lastNode = last
+		return nil
+	}
+}
+
+// WithSize sets the digest size in bytes, between 1 and Size.
+func WithSize(size int) Option {
+	return func(d *digest) error {
+		if size < 1 || size > Size {
+			return errors.New("blake2b: invalid digest size")
+		}
+		d.size = size
+		return nil
+	}
+}
+
+// WithKey sets the key of the digest, at most KeySize bytes long.
+func WithKey(key []byte) Option {
+	return func(d *digest) error {
+		if len(key) > KeySize {
+			return errors.New("blake2b: invalid key size")
+		}
+		d.key = append([]byte(nil), key...)
+		return nil
+	}
+}
+
+// WithSalt sets the salt of the digest, at most SaltSize bytes long.
+func WithSalt(salt []byte) Option {
+	return func(d *digest) error {
+		if len(salt) > SaltSize {
+			return errors.New("blake2b: invalid salt size")
+		}
+		d.salt = append([]byte(nil), salt...)
+		return nil
+	}
+}
+
+// WithPersonal sets the personalization string of the digest, at most
+// PersonalSize bytes long.
+func WithPersonal(personal []byte) Option {
+	return func(d *digest) error {
+		if len(personal) > PersonalSize {
+			return errors.New("blake2b: invalid personalization size")
+		}
+		d.personal = append([]byte(nil), personal...)
+		return nil
+	}
+}
+
+// Salt returns a copy of the salt configured with WithSalt. There is
+// deliberately no accessor for the key.
+func (d *digest) Salt() []byte {
+	return append([]byte{}, d.salt...)
+}
+
+// Personal returns a copy of the personalization configured with
+// WithPersonal.
+func (d *digest) Personal() []byte {
+	return append([]byte{}, d.personal...)
+}
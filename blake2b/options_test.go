@@ -0,0 +1,292 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMaxInputWrite(t *testing.T) {
+	input := make([]byte, 250)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+
+	h, err := NewWith(WithMaxInput(200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := h.Write(input[:150]); n != 150 || err != nil {
+		t.Fatalf("Write below limit: n=%d, err=%v", n, err)
+	}
+	if n, err := h.Write(input[150:]); n != 50 || err != ErrInputLimit {
+		t.Fatalf("Write past limit: n=%d, err=%v", n, err)
+	}
+	if n, err := h.Write(input[:1]); n != 0 || err != ErrInputLimit {
+		t.Fatalf("Write at limit: n=%d, err=%v", n, err)
+	}
+
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+	expected := "FB3C1F0F56A56F8E316FDF5D853C8C872C39635D083634C3904FC3AC07D1B578E85FF0E480E92D44ADE33B62E893EE32343E79DDF6EF292E89B582D312502314"
+	if actual != expected {
+		t.Errorf("bad hash of absorbed prefix: expected=%s, actual=%s", expected, actual)
+	}
+
+	h.Reset()
+	if n, err := h.Write(input[:200]); n != 200 || err != nil {
+		t.Errorf("Write after Reset: n=%d, err=%v", n, err)
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	h, err := NewWith(WithSize(20), WithKey([]byte("key")), WithPersonal([]byte("me")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Size() != 20 {
+		t.Errorf("bad size: %d", h.Size())
+	}
+	h.Write([]byte("abc"))
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+	expected := "52881C6119AE545A9DA2ECA91B52B382C08FB72E"
+	if actual != expected {
+		t.Errorf("bad hash: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestWithOptionsErrors(t *testing.T) {
+	for _, opt := range []Option{
+		WithSize(0),
+		WithSize(Size + 1),
+		WithKey(make([]byte, KeySize+1)),
+		WithSalt(make([]byte, SaltSize+1)),
+		WithPersonal(make([]byte, PersonalSize+1)),
+	} {
+		if _, err := NewWith(opt); err == nil {
+			t.Error("expected error for invalid option")
+		}
+	}
+}
+
+func TestSaltPersonal(t *testing.T) {
+	salt := []byte("pepper")
+	personal := []byte("my app v1")
+	h, err := NewWith(WithSalt(salt), WithPersonal(personal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := h.(*digest)
+	if actual := d.Salt(); !bytes.Equal(actual, salt) {
+		t.Errorf("bad salt: expected=%q, actual=%q", salt, actual)
+	}
+	if actual := d.Personal(); !bytes.Equal(actual, personal) {
+		t.Errorf("bad personalization: expected=%q, actual=%q", personal, actual)
+	}
+
+	d.Salt()[0] ^= 1
+	d.Personal()[0] ^= 1
+	salt[0] ^= 1
+	personal[0] ^= 1
+	if actual := d.Salt(); !bytes.Equal(actual, []byte("pepper")) {
+		t.Errorf("salt is not a defensive copy: %q", actual)
+	}
+	if actual := d.Personal(); !bytes.Equal(actual, []byte("my app v1")) {
+		t.Errorf("personalization is not a defensive copy: %q", actual)
+	}
+
+	h.Write([]byte("abc"))
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+	expected := "73EDB8F146BFBE476EF8FC6825E26164485224790263FD90271495252C67F2E71098B6735B69A6C49B982B543908956D262327781790A0B8E5B505476918D0C2"
+	if actual != expected {
+		t.Errorf("bad salted hash: expected=%s, actual=%s", expected, actual)
+	}
+
+	d = New().(*digest)
+	if len(d.Salt()) != 0 || len(d.Personal()) != 0 {
+		t.Error("unset salt or personalization is not empty")
+	}
+}
+
+func TestSumLength(t *testing.T) {
+	for size := 1; size <= Size; size++ {
+		for _, opts := range [][]Option{
+			{WithSize(size)},
+			{WithSize(size), WithKey([]byte("key"))},
+			{WithSize(size), WithSalt([]byte("salt")), WithPersonal([]byte("personal"))},
+		} {
+			h, err := NewWith(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if h.Size() != size {
+				t.Errorf("bad Size: expected=%d, actual=%d", size, h.Size())
+			}
+			h.Write([]byte("abc"))
+			if n := len(h.Sum(nil)); n != h.Size() {
+				t.Errorf("bad Sum length (size %d): %d", size, n)
+			}
+			if n := len(h.Sum([]byte("prefix"))); n != len("prefix")+h.Size() {
+				t.Errorf("bad appended Sum length (size %d): %d", size, n)
+			}
+		}
+	}
+}
+
+func TestWithBufferBlocks(t *testing.T) {
+	input := make([]byte, 5000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	for _, n := range []int{0, 1, 127, 128, 129, 256, 257, 1000, 5000} {
+		h := New()
+		h.Write(input[:n])
+		expected := h.Sum(nil)
+
+		for _, blocks := range []int{1, 2, 3, 8, 64} {
+			h, err := NewWith(WithBufferBlocks(blocks))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, chunk := range [][]byte{input[:n/3], input[n/3 : n/2], input[n/2 : n]} {
+				h.Write(chunk)
+				h.Sum(nil)
+			}
+			if actual := h.Sum(nil); !bytes.Equal(actual, expected) {
+				t.Errorf("bad hash (%d blocks, len %d): expected=%X, actual=%X", blocks, n, expected, actual)
+			}
+		}
+	}
+}
+
+func TestWithBufferBlocksInvalid(t *testing.T) {
+	for _, n := range []int{-1, 0, maxBufferBlocks + 1} {
+		if _, err := NewWith(WithBufferBlocks(n)); err == nil {
+			t.Errorf("expected error for %d blocks", n)
+		}
+	}
+}
+
+func TestWithBigEndianOutput(t *testing.T) {
+	input := []byte("abc")
+	for _, size := range []int{Size, 20} {
+		h, _ := NewWith(WithSize(size))
+		h.Write(input)
+		// The words of a truncated digest are swapped before truncation.
+		dd := *h.(*digest)
+		standard := dd.checkSum()
+		expected := make([]byte, Size)
+		for i := range expected {
+			expected[i] = standard[i/8*8+7-i%8]
+		}
+
+		h, err := NewWith(WithSize(size), WithBigEndianOutput())
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Write(input)
+		if actual := h.Sum(nil); !bytes.Equal(actual, expected[:size]) {
+			t.Errorf("bad big-endian hash (size %d): expected=%X, actual=%X", size, expected[:size], actual)
+		}
+	}
+}
+
+func TestWithLastNode(t *testing.T) {
+	sum := func(opts ...Option) string {
+		h, err := NewWith(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Write([]byte("abc"))
+		return fmt.Sprintf("%X", h.Sum(nil))
+	}
+	unset := sum(WithLastNode(false))
+	if expected := sum(); unset != expected {
+		t.Errorf("bad hash without last node flag: expected=%s, actual=%s", expected, unset)
+	}
+	expected := "0C72C218C5D1C50F3F4ABB0645C1A1178C901C6995D3E2CB70C3C5572C9AD1FA4BDC2D8F59DB5AB0DEBCE9ED4C043ED2713954B333CA07B815D91218AC3E3DE4"
+	if actual := sum(WithLastNode(true)); actual != expected {
+		t.Errorf("bad hash with last node flag: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestMaxInputKeyed(t *testing.T) {
+	h, err := NewWith(WithKey([]byte("key")), WithMaxInput(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := h.(*digest)
+	for i := 0; i < 2; i++ {
+		if d.written != 0 {
+			t.Errorf("key block counted as %d bytes of input", d.written)
+		}
+		if n, err := h.Write(make([]byte, 10)); n != 10 || err != nil {
+			t.Errorf("Write up to limit: n=%d, err=%v", n, err)
+		}
+		if n, err := h.Write([]byte{0}); n != 0 || err != ErrInputLimit {
+			t.Errorf("Write past limit: n=%d, err=%v", n, err)
+		}
+		h.Reset()
+	}
+
+	ref := NewKeyed([]byte("key"))
+	ref.Write(make([]byte, 10))
+	h.Write(make([]byte, 10))
+	if actual, expected := h.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad keyed hash: expected=%X, actual=%X", expected, actual)
+	}
+}
+
+// keyedSaltedVectors are Blake2b-512 digests of the first i%251 bytes
+// under the key 00..3F, the salt 00..0F and the personalization
+// "personalization!", from Python's hashlib.blake2b.
+var keyedSaltedVectors = []struct {
+	n        int
+	expected string
+}{
+	{0, "F300C6D1793B2A82D1C2FD465A664DCDC48B412BD1120B0CE430717EA1100CD99932D3F2CD3C63A7141CC321221D8433061B41B656E95975A62B24B8BCEE2B08"},
+	{1, "36DE8575FE062C8A7F140EB12E818E007CECA581DC47FBE63E06C8F76C67B79D0564A3A291D0327330E80B068F4BDC100126EF3DC369932A7CE24C9AEECCCD91"},
+	{127, "E55051C95C58B18F81243FE5A0B1BD9E218EF615AF3557D36ACD25DFD84134088B3E302C321DCCF011CFFB98BD58C0F036678B7DA0F890F1C43A75EDBBA1EA71"},
+	{128, "BCD8AB558ED2658AF44BB5B344FC0B36DDD6B085FE18ACFA08C56E1204449B4824BDF2D849CBBC9B080B3AB265CD5B9E6E0455F3B4E62DB4DAFDD2ED4908887A"},
+	{129, "E42BEAE39A40CDF1FDE7EFE5DABFF4DBFD038A76805C75013830A3BB3D61F1943020F1620FC3BE66CE0B4D14F7558CF66536C6E9BA73B38F79B484F87AA08D8B"},
+	{255, "7798C4124AC2F6DB15B475EE0FCDC7B7C25843D739589E8C8FC9F156745D2E7BB95D0852D1AAD3E7EC22662498B20B40BD70C0A0653735F86A8EEEDD09A3BB99"},
+	{256, "9872056A27076E0AD98D77BE52281EE74C4FBDD838DC53AAC8A8300346AB6242D9A5F5B8B43F9D30E87347F40DB7060AC29541E010ABFE78E13C9D7F8F1C0F24"},
+	{1000, "F743D99C4EF944061F02206BA2DC15AC6ACA8694050641DC319BC1D812DFAC85C9B5D3005682C0FD0CD47FFB7B3C335ADA2539E89E32E283EE67FAE9C75849CF"},
+}
+
+func TestKeyedSalted(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	h, err := NewWith(WithKey(key), WithSalt(key[:SaltSize]), WithPersonal([]byte("personalization!")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range keyedSaltedVectors {
+		input := make([]byte, v.n)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+		h.Reset()
+		h.Write(input)
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.expected {
+			t.Errorf("bad hash (%d): expected=%s, actual=%s", v.n, v.expected, actual)
+		}
+	}
+}
+
+func TestKeyedSaltedShort(t *testing.T) {
+	h, err := NewWith(WithSize(32), WithKey([]byte("key")), WithSalt([]byte("salt")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Write([]byte("abc"))
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+	expected := "766EF532772602D7732A1033DE6E946CE2245B081B2E8751ABFB3D1C7C375B32"
+	if actual != expected {
+		t.Errorf("bad hash: expected=%s, actual=%s", expected, actual)
+	}
+	if unsalted := fmt.Sprintf("%X", MAC([]byte("key"), []byte("abc"), 32)); unsalted == actual {
+		t.Error("salt did not change the keyed hash")
+	}
+}
@@ -0,0 +1,149 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"math/rand"
+	"testing"
+)
+
+var vectors2bp = []struct {
+	length int
+	keyed  bool
+	output string
+}{
+	{0, false, "B5EF811A8038F70B628FA8B294DAAE7492B1EBE343A80EAABBF1F6AE664DD67B9D90B0120791EAB81DC96985F28849F6A305186A85501B405114BFA678DF9380"},
+	{1, false, "A139280E72757B723E6473D5BE59F36E9D50FC5CD7D4585CBC09804895A36C521242FB2789F85CB9E35491F31D4A6952F9D8E097AEF94FA1CA0B12525721F03D"},
+	{127, false, "EA64B003A135766121CFBCCBDC08DCA2402926BE78CEA3D0A7253D9EC9E63B8ACDD994559917E0E03B5E155F944D7198D99245A794CE19C9B4DF4DA4A3399334"},
+	{128, false, "05AD0F271FAF7E361320518452813FF9FB9976AC378050B6EEFB05F7867B577B8F14475794CFF61B2BC062D346A7C65C6E0067C60A374AF7940F10AA449D5FB9"},
+	{511, false, "C86D92D70AB59BA357A987BD6F90E938A8ED5A8541BB387648A992F11063BFA9B339562EFACCB7553C9E4AF5F02B16A73B51C2665D9E817BFC94C5B192B43A5F"},
+	{512, false, "61C4DABACDFB1352185AAE9DBC04B348AF681478B0C4AA7291C7BAB11783E8AFE05830D87B6E003BBD95A08D9DB6B053F12E75602FD5F1C1F49D39CD6C12B40B"},
+	{513, false, "C62CF13185F8EB971737218C9AE187F6447DFD286D206C7D42F442C719527C59D4655CA5829BF3912D284B916F5BDAA36672363BDCA29B0ED2047BA98404A2AD"},
+	{1000, false, "440C4C3A7A50159B43A3B80E63083FA88B7E644490061CE763E92426D1FA9F034D0A3A4F94D99042B98D068DA35C5AF694EA9E7F51B8551AF5C99C2EEF95024D"},
+	{40000, false, "8BC3F408FAA13194244E8E86954660A41282F779753F16F02CD9E5E9C0CA774FBDCF3201A0D3FCD11746F1F0CDA7B78B5E00FA7D592EAE2C77A8B7B6CE51604A"},
+	{0, true, "9D9461073E4EB640A255357B839F394B838C6FF57C9B686A3F76107C1066728F3C9956BD785CBC3BF79DC2AB578C5A0C063B9D9C405848DE1DBE821CD05C940A"},
+	{1, true, "FF8E90A37B94623932C59F7559F26035029C376732CB14D41602001CBB73ADB79293A2DBDA5F60703025144D158E2735529596251C73C0345CA6FCCB1FB1E97E"},
+	{513, true, "A55CF608515924AC36C056E9E8576D8E85DEF53D168912F770AD68BBD5D61973A188BB14F497C2585075FCA439C6160ABF4695FD631E527D759C18803C2DBCFC"},
+	{40000, true, "D5149373680D34770404339A20202627CC588B8A7533E8D85C78961B98B0267BEBBCE33F6E57F3D18C8C79905A56EAA422151C3F30F67B53F1D3A09502303C4A"},
+}
+
+func TestBlake2bp(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for _, v := range vectors2bp {
+		input := make([]byte, v.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+
+		h := NewP()
+		if v.keyed {
+			h = NewPKeyed(key)
+		}
+		h.Write(input)
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.output {
+			t.Errorf("bad hash (%d, keyed %v): expected=%s, actual=%s", v.length, v.keyed, v.output, actual)
+		}
+	}
+}
+
+// referenceP computes the Blake2bp checksum sequentially.
+func referenceP(input []byte) []byte {
+	tree := Tree{Fanout: parallelism, MaxDepth: 2}
+	var leaves [parallelism]hash.Hash
+	for i := range leaves {
+		leaves[i] = tree.LeafHasher(uint64(i), i == parallelism-1)
+	}
+	for i := 0; i < len(input); i += BlockSize {
+		end := i + BlockSize
+		if end > len(input) {
+			end = len(input)
+		}
+		leaves[i/BlockSize%parallelism].Write(input[i:end])
+	}
+	root := tree.RootHasher()
+	for _, leaf := range leaves {
+		root.Write(leaf.Sum(nil))
+	}
+	return root.Sum(nil)
+}
+
+// Run with -race to check the concurrent leaf scheduling.
+func TestBlake2bpRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		input := make([]byte, rng.Intn(4*parallelThreshold))
+		rng.Read(input)
+
+		h := NewP()
+		for rest := input; len(rest) > 0; {
+			n := rng.Intn(len(rest) + 1)
+			h.Write(rest[:n])
+			rest = rest[n:]
+		}
+		if actual, expected := h.Sum(nil), referenceP(input); !bytes.Equal(actual, expected) {
+			t.Fatalf("bad hash (%d): expected=%X, actual=%X", len(input), expected, actual)
+		}
+	}
+}
+
+var vectors2bp256 = []struct {
+	length int
+	keyed  bool
+	output string
+}{
+	{0, false, "E3F5E2E3C4336E2B8EEC91ECB154E40C8B1FA34091B286BCA5B67D5A7F87FF98"},
+	{1, false, "EDBDF8679498D881F78229721CAA18896376F493714FC153D953227F1D49F519"},
+	{128, false, "8D20C0831FF252B3701F0C0DAE63C46BEB5359FC736917B13A96EF5474E38DB0"},
+	{513, false, "C071754DB5F595D2B3C95A259F1B79B50A96C1435625E89127725731B94C951E"},
+	{1000, false, "1A6CE3255F2054BF866495CD964809023CBC29021D008298F70EAFB85A5F8671"},
+	{513, true, "7A911EFC17C4533E89052386AB01F0A629C665DCBC7C9446C27F1CE3076D9AEE"},
+}
+
+func TestBlake2bp256(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for _, v := range vectors2bp256 {
+		input := make([]byte, v.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+
+		h := NewPSized(32, nil)
+		if v.keyed {
+			h = NewPSized(32, key)
+		}
+		h.Write(input)
+		sum := h.Sum(nil)
+		if actual := fmt.Sprintf("%X", sum); actual != v.output {
+			t.Errorf("bad hash (%d, keyed %v): expected=%s, actual=%s", v.length, v.keyed, v.output, actual)
+		}
+
+		full := NewP()
+		if v.keyed {
+			full = NewPKeyed(key)
+		}
+		full.Write(input)
+		if bytes.Equal(sum, full.Sum(nil)[:32]) {
+			t.Errorf("sized hash (%d) is a truncation of the full-length one", v.length)
+		}
+	}
+}
+
+func TestNewPSizedInvalid(t *testing.T) {
+	for _, size := range []int{0, Size + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for size %d", size)
+				}
+			}()
+			NewPSized(size, nil)
+		}()
+	}
+}
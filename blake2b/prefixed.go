@@ -0,0 +1,42 @@
+package blake2b
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+// prefixed frames each Write with the uvarint length of its data.
+type prefixed struct {
+	d *digest
+}
+
+// NewPrefixed returns a new hash.Hash computing the Blake2b checksum of
+// its input where every Write call, including empty ones, is preceded by
+// the uvarint length of its data. The digest thus depends on how the data
+// is split across calls, and callers must issue one Write per meaningful
+// value.
+func NewPrefixed() hash.Hash {
+	return &prefixed{New().(*digest)}
+}
+
+func (p *prefixed) Write(buf []byte) (int, error) {
+	var length [binary.MaxVarintLen64]byte
+	p.d.Write(length[:binary.PutUvarint(length[:], uint64(len(buf)))])
+	return p.d.Write(buf)
+}
+
+func (p *prefixed) Sum(buf []byte) []byte {
+	return p.d.Sum(buf)
+}
+
+func (p *prefixed) Reset() {
+	p.d.Reset()
+}
+
+func (p *prefixed) Size() int {
+	return p.d.Size()
+}
+
+func (p *prefixed) BlockSize() int {
+	return p.d.BlockSize()
+}
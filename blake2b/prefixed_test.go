@@ -0,0 +1,46 @@
+package blake2b
+
+import (
+	"bytes"
+	"hash"
+	"testing"
+)
+
+func prefixedSum(parts ...string) []byte {
+	h := NewPrefixed()
+	for _, part := range parts {
+		h.Write([]byte(part))
+	}
+	return h.Sum(nil)
+}
+
+func TestPrefixed(t *testing.T) {
+	a := prefixedSum("ab", "c")
+	b := prefixedSum("a", "bc")
+	c := prefixedSum("abc")
+	if bytes.Equal(a, b) || bytes.Equal(a, c) || bytes.Equal(b, c) {
+		t.Error("different groupings produced the same digest")
+	}
+	if !bytes.Equal(a, prefixedSum("ab", "c")) {
+		t.Error("NewPrefixed is not deterministic")
+	}
+	if bytes.Equal(c, prefixedSum("abc", "")) {
+		t.Error("empty Write did not change the digest")
+	}
+
+	h := New()
+	h.Write([]byte{2, 'a', 'b', 1, 'c'})
+	if expected := h.Sum(nil); !bytes.Equal(a, expected) {
+		t.Errorf("bad framing: expected=%X, actual=%X", expected, a)
+	}
+}
+
+func TestPrefixedReset(t *testing.T) {
+	var h hash.Hash = NewPrefixed()
+	h.Write([]byte("junk"))
+	h.Reset()
+	h.Write([]byte("abc"))
+	if expected := prefixedSum("abc"); !bytes.Equal(h.Sum(nil), expected) {
+		t.Error("Reset did not clear the state")
+	}
+}
@@ -0,0 +1,13 @@
+package blake2b
+
+// Prehash returns the 64-byte Blake2b checksum of the domain byte
+// followed by message, for prehash signature schemes in the style of
+// Ed25519ph. Distinct domain bytes separate the digests of different
+// protocols or message types signed with the same key.
+func Prehash(domain byte, message []byte) []byte {
+	d := newDigest()
+	d.Reset()
+	d.Write([]byte{domain})
+	d.Write(message)
+	return d.Sum(nil)
+}
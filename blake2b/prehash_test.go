@@ -0,0 +1,29 @@
+package blake2b
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrehash(t *testing.T) {
+	message := bytes.Repeat([]byte("sign me "), 50)
+	h := New()
+	h.Write([]byte{7})
+	h.Write(message)
+	expected := h.Sum(nil)
+	if actual := Prehash(7, message); !bytes.Equal(actual, expected) {
+		t.Errorf("bad prehash: expected=%X, actual=%X", expected, actual)
+	}
+	if len(expected) != 64 {
+		t.Errorf("bad prehash length: %d", len(expected))
+	}
+
+	seen := map[string]byte{}
+	for domain := 0; domain < 256; domain++ {
+		sum := string(Prehash(byte(domain), message))
+		if other, ok := seen[sum]; ok {
+			t.Fatalf("domains %d and %d share a prehash", other, domain)
+		}
+		seen[sum] = byte(domain)
+	}
+}
@@ -0,0 +1,105 @@
+package blake2b
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// HashReader returns the Blake2b checksum of size bytes of the data read
+// from r until EOF.
+func HashReader(r io.Reader, size int) ([]byte, error) {
+	return HashReaderProgress(r, size, 0, nil)
+}
+
+// HashReaderProgress is like HashReader, but reports the total number of
+// bytes read so far to progress each time at least every more bytes have
+// been read, and once more at EOF. If every is not positive, progress is
+// only called at EOF.
+//
+// progress is called synchronously from the read loop and should return
+// quickly, for example by handing the value to another goroutine.
+func HashReaderProgress(r io.Reader, size int, every int64, progress func(bytesRead int64)) ([]byte, error) {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, readBufSize)
+	var total, reported int64
+	for {
+		n, err := r.Read(buf)
+		h.Write(buf[:n])
+		total += int64(n)
+		if progress != nil && every > 0 && total-reported >= every {
+			progress(total)
+			reported = total
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if progress != nil && (reported != total || total == 0) {
+		progress(total)
+	}
+	return h.Sum(nil), nil
+}
+
+// HashReaderContext is like HashReader, but checks ctx before every read
+// and returns ctx.Err() once it is done. A Read that blocks is not
+// interrupted, so r should return in bounded time, for example through a
+// read deadline, for cancellation to take effect promptly.
+func HashReaderContext(ctx context.Context, r io.Reader, size int) ([]byte, error) {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, readBufSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n, err := r.Read(buf)
+		h.Write(buf[:n])
+		if err == io.EOF {
+			return h.Sum(nil), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// HashAndCountLines returns the Blake2b checksum of size bytes of the
+// data read from r until EOF, together with its number of lines, in a
+// single pass. Every newline ends a line, and trailing data without a
+// final newline counts as one more line, so "a\nb" and "a\nb\n" both
+// have two lines and empty input has none.
+func HashAndCountLines(r io.Reader, size int) (digest []byte, lines int, err error) {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		return nil, 0, err
+	}
+	buf := make([]byte, readBufSize)
+	var last byte = '\n'
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	if last != '\n' {
+		lines++
+	}
+	return h.Sum(nil), lines, nil
+}
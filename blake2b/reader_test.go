@@ -0,0 +1,145 @@
+package blake2b
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestHashReader(t *testing.T) {
+	input := bytes.Repeat([]byte("0123456789"), 10000)
+	ref, _ := NewWith(WithSize(32))
+	ref.Write(input)
+	expected := ref.Sum(nil)
+
+	actual, err := HashReader(bytes.NewReader(input), 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+	}
+
+	if _, err := HashReader(iotest.TimeoutReader(bytes.NewReader(input)), 32); err != iotest.ErrTimeout {
+		t.Errorf("expected read error, got %v", err)
+	}
+}
+
+func TestHashReaderProgress(t *testing.T) {
+	input := bytes.Repeat([]byte("0123456789"), 10000)
+	var totals []int64
+	progress := func(n int64) { totals = append(totals, n) }
+
+	actual, err := HashReaderProgress(iotest.HalfReader(bytes.NewReader(input)), Size, 4096, progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := HashReader(bytes.NewReader(input), Size)
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+	}
+
+	if len(totals) < 2 {
+		t.Fatalf("too few progress calls: %v", totals)
+	}
+	for i := 1; i < len(totals); i++ {
+		if totals[i] <= totals[i-1] {
+			t.Errorf("progress is not increasing: %v", totals)
+			break
+		}
+	}
+	if last := totals[len(totals)-1]; last != int64(len(input)) {
+		t.Errorf("bad final progress: expected=%d, actual=%d", len(input), last)
+	}
+
+	totals = nil
+	HashReaderProgress(bytes.NewReader(nil), Size, 4096, progress)
+	if len(totals) != 1 || totals[0] != 0 {
+		t.Errorf("bad progress for empty input: %v", totals)
+	}
+}
+
+func TestHashAndCountLines(t *testing.T) {
+	for _, v := range []struct {
+		input string
+		lines int
+	}{
+		{"", 0},
+		{"\n", 1},
+		{"one", 1},
+		{"one\n", 1},
+		{"one\ntwo", 2},
+		{"one\ntwo\n", 2},
+		{"\n\n\n", 3},
+		{strings.Repeat("record\n", 10000), 10000},
+		{strings.Repeat("record\n", 10000) + "tail", 10001},
+	} {
+		digest, lines, err := HashAndCountLines(iotest.OneByteReader(strings.NewReader(v.input)), 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines != v.lines {
+			t.Errorf("bad line count (%q): expected=%d, actual=%d", v.input, v.lines, lines)
+		}
+		if expected := HashAll([]byte(v.input), 32); !bytes.Equal(digest, expected) {
+			t.Errorf("bad hash (%q): expected=%X, actual=%X", v.input, expected, digest)
+		}
+	}
+}
+
+func TestHashAndCountLinesError(t *testing.T) {
+	readErr := errors.New("read failed")
+	if _, _, err := HashAndCountLines(iotest.ErrReader(readErr), 32); err != readErr {
+		t.Errorf("expected=%v, actual=%v", readErr, err)
+	}
+	if _, _, err := HashAndCountLines(strings.NewReader(""), 0); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
+
+// slowReader returns one zero byte per Read after a delay, forever.
+type slowReader struct {
+	delay time.Duration
+}
+
+func (r slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	p[0] = 0
+	return 1, nil
+}
+
+func TestHashReaderContext(t *testing.T) {
+	input := strings.Repeat("context ", 10000)
+	digest, err := HashReaderContext(context.Background(), strings.NewReader(input), 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := HashAll([]byte(input), 32); !bytes.Equal(digest, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, digest)
+	}
+}
+
+func TestHashReaderContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := HashReaderContext(ctx, strings.NewReader("data"), 32); err != context.Canceled {
+		t.Errorf("expected=%v, actual=%v", context.Canceled, err)
+	}
+}
+
+func TestHashReaderContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := HashReaderContext(ctx, slowReader{time.Millisecond}, 32)
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected=%v, actual=%v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("cancellation took %v", elapsed)
+	}
+}
@@ -0,0 +1,119 @@
+package blake2b
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"hash"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadFrom(t *testing.T) {
+	input := strings.Repeat("the quick brown fox ", 5000)
+
+	h := New()
+	n, err := h.(io.ReaderFrom).ReadFrom(strings.NewReader(input))
+	if n != int64(len(input)) || err != nil {
+		t.Fatalf("ReadFrom: n=%d, err=%v", n, err)
+	}
+
+	ref := New()
+	ref.Write([]byte(input))
+	if actual, expected := h.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+	}
+}
+
+type errReader struct {
+	data []byte
+	err  error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestReadFromError(t *testing.T) {
+	input := []byte(strings.Repeat("x", 1000))
+	readErr := errors.New("read failed")
+
+	h := New()
+	n, err := h.(io.ReaderFrom).ReadFrom(&errReader{input, readErr})
+	if n != int64(len(input)) || err != readErr {
+		t.Fatalf("ReadFrom: n=%d, err=%v", n, err)
+	}
+
+	ref := New()
+	ref.Write(input)
+	if actual, expected := h.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+	}
+}
+
+func TestReadFromBufio(t *testing.T) {
+	input := []byte(strings.Repeat("the quick brown fox ", 5000))
+	for _, size := range []int{16, 100, 4096} {
+		h := New()
+		n, err := h.(io.ReaderFrom).ReadFrom(bufio.NewReaderSize(bytes.NewReader(input), size))
+		if n != int64(len(input)) || err != nil {
+			t.Fatalf("ReadFrom: n=%d, err=%v", n, err)
+		}
+
+		ref := New()
+		io.Copy(ref, struct{ io.Reader }{bytes.NewReader(input)})
+		if actual, expected := h.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+			t.Errorf("bad hash (buffer %d): expected=%X, actual=%X", size, expected, actual)
+		}
+	}
+}
+
+func TestReadFromBufioLimit(t *testing.T) {
+	input := []byte(strings.Repeat("x", 1000))
+	h, _ := NewWith(WithMaxInput(300))
+	br := bufio.NewReader(bytes.NewReader(input))
+	n, err := h.(io.ReaderFrom).ReadFrom(br)
+	if n != 300 || err != ErrInputLimit {
+		t.Fatalf("ReadFrom: n=%d, err=%v", n, err)
+	}
+	if rest, _ := io.ReadAll(br); len(rest) != 700 {
+		t.Errorf("bytes past the limit were consumed: %d left", len(rest))
+	}
+}
+
+func TestReadFromBufioError(t *testing.T) {
+	input := []byte(strings.Repeat("x", 1000))
+	readErr := errors.New("read failed")
+	h := New()
+	n, err := h.(io.ReaderFrom).ReadFrom(bufio.NewReader(&errReader{input, readErr}))
+	if n != int64(len(input)) || err != readErr {
+		t.Fatalf("ReadFrom: n=%d, err=%v", n, err)
+	}
+}
+
+func benchmarkReadFrom(b *testing.B, wrap func(io.Reader) io.Reader) {
+	input := make([]byte, 1<<20)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	h := New().(io.ReaderFrom)
+	for i := 0; i < b.N; i++ {
+		h.(hash.Hash).Reset()
+		h.ReadFrom(wrap(bufio.NewReaderSize(bytes.NewReader(input), 64<<10)))
+	}
+}
+
+func BenchmarkReadFromBufio(b *testing.B) {
+	benchmarkReadFrom(b, func(r io.Reader) io.Reader { return r })
+}
+
+// BenchmarkReadFromBufioHidden hides the *bufio.Reader, so its data is
+// copied to the read buffer of the digest first.
+func BenchmarkReadFromBufioHidden(b *testing.B) {
+	benchmarkReadFrom(b, func(r io.Reader) io.Reader { return struct{ io.Reader }{r} })
+}
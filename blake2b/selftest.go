@@ -0,0 +1,40 @@
+package blake2b
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+)
+
+// EmptyHash512 is the Blake2b checksum of the empty input. It is
+// computed and checked by SelfTest when the package is initialized.
+var EmptyHash512 []byte
+
+// Known answers used by SelfTest.
+var selfTestVectors = []struct {
+	input  string
+	output string
+}{
+	{"", "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+	{"abc", "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+}
+
+func init() {
+	if err := SelfTest(); err != nil {
+		panic(err)
+	}
+	EmptyHash512 = New().Sum(nil)
+}
+
+// SelfTest checks the Blake2b implementation against known answers.
+func SelfTest() error {
+	for _, v := range selfTestVectors {
+		expected, _ := hex.DecodeString(v.output)
+		h := New()
+		h.Write([]byte(v.input))
+		if !bytes.Equal(h.Sum(nil), expected) {
+			return errors.New("blake2b: self-test failed")
+		}
+	}
+	return nil
+}
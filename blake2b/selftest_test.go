@@ -0,0 +1,61 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEmptyHash512(t *testing.T) {
+	h := New()
+	h.Write(nil)
+	if d := h.Sum(nil); !bytes.Equal(d, EmptyHash512) {
+		t.Errorf("bad empty hash: expected=%X, actual=%X", EmptyHash512, d)
+	}
+	if len(EmptyHash512) != 64 {
+		t.Errorf("bad empty hash length: %d", len(EmptyHash512))
+	}
+}
+
+// rfc7693Seq returns the deterministic test input of length n for seed,
+// generated as in the self-test of RFC 7693, Appendix E.
+func rfc7693Seq(n int, seed uint32) []byte {
+	a, b := 0xDEAD4BAD*seed, uint32(1)
+	out := make([]byte, n)
+	for i := range out {
+		a, b = b, a+b
+		out[i] = byte(b >> 24)
+	}
+	return out
+}
+
+// TestRFC7693SelfTest hashes the unkeyed and keyed digests of every
+// combination of digest and input length from RFC 7693, Appendix E, into
+// one 32-byte digest-of-digests and checks it against the published value.
+func TestRFC7693SelfTest(t *testing.T) {
+	root, _ := NewWith(WithSize(32))
+	for _, size := range []int{20, 32, 48, 64} {
+		for _, n := range []int{0, 3, 128, 129, 255, 1024} {
+			input := rfc7693Seq(n, uint32(n))
+
+			h, _ := NewWith(WithSize(size))
+			h.Write(input)
+			root.Write(h.Sum(nil))
+
+			h, _ = NewWith(WithSize(size), WithKey(rfc7693Seq(size, uint32(size))))
+			h.Write(input)
+			root.Write(h.Sum(nil))
+		}
+	}
+	actual := fmt.Sprintf("%X", root.Sum(nil))
+	expected := "C23A7800D98123BD10F506C61E29DA5603D763B8BBAD2E737F5E765A7BCCD475"
+	if actual != expected {
+		t.Errorf("bad digest of digests: expected=%s, actual=%s", expected, actual)
+	}
+}
@@ -0,0 +1,37 @@
+package blake2b
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Shuffle pseudo-randomizes the order of n elements with a Fisher-Yates
+// shuffle, calling swap to exchange the elements with indexes i and j.
+// The randomness is read from the BLAKE2Xb output stream of seed, so the
+// same seed always yields the same permutation. Indexes are drawn by
+// rejection sampling and carry no modulo bias. It panics if n < 0.
+func Shuffle(seed []byte, n int, swap func(i, j int)) {
+	if n < 0 {
+		panic("blake2b: invalid argument to Shuffle")
+	}
+	x := NewXOF(OutputLengthUnknown)
+	x.Write(seed)
+	for i := n - 1; i > 0; i-- {
+		j := int(uniform(x, uint64(i)+1))
+		swap(i, j)
+	}
+}
+
+// uniform returns a uniformly distributed integer in [0, bound) read from
+// the output of x, skipping the values at the top of the uint64 range
+// that would bias the remainder.
+func uniform(x *XOF, bound uint64) uint64 {
+	limit := math.MaxUint64 - math.MaxUint64%bound
+	var b [8]byte
+	for {
+		x.Read(b[:])
+		if v := binary.LittleEndian.Uint64(b[:]); v < limit {
+			return v % bound
+		}
+	}
+}
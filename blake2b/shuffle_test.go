@@ -0,0 +1,40 @@
+package blake2b
+
+import (
+	"reflect"
+	"testing"
+)
+
+func shuffled(seed string, n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	Shuffle([]byte(seed), n, func(i, j int) {
+		perm[i], perm[j] = perm[j], perm[i]
+	})
+	return perm
+}
+
+func TestShuffleDeterministic(t *testing.T) {
+	a, b := shuffled("seed", 100), shuffled("seed", 100)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed gave different permutations: %v, %v", a, b)
+	}
+	if c := shuffled("other seed", 100); reflect.DeepEqual(a, c) {
+		t.Error("different seeds gave the same permutation")
+	}
+}
+
+func TestShufflePermutation(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		perm := shuffled("seed", n)
+		seen := make([]bool, n)
+		for _, v := range perm {
+			if v < 0 || v >= n || seen[v] {
+				t.Fatalf("not a permutation (%d): %v", n, perm)
+			}
+			seen[v] = true
+		}
+	}
+}
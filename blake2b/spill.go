@@ -0,0 +1,108 @@
+package blake2b
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// A SpillWriter hashes the data written to it and keeps a copy, in
+// memory up to a limit and in a temporary file beyond it. It serves
+// framing formats that put the length and checksum of the content before
+// the content: once Finish reports them, the caller writes the header
+// and then copies the content.
+type SpillWriter struct {
+	d        *digest
+	memLimit int
+	dir      string
+	mem      []byte
+	file     *os.File
+	n        int64
+	finished bool
+}
+
+// NewSpillWriter returns a SpillWriter computing the Blake2b checksum of
+// size bytes. It keeps up to memLimit bytes in memory and moves the data
+// to a temporary file in dir, or in os.TempDir if dir is empty, once the
+// input grows beyond that. It panics if size is not between 1 and Size.
+func NewSpillWriter(size, memLimit int, dir string) *SpillWriter {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		panic(err)
+	}
+	return &SpillWriter{d: h.(*digest), memLimit: memLimit, dir: dir}
+}
+
+// Write stores and hashes buf. Only the bytes that were stored are
+// hashed, so the checksum always matches the content. It returns
+// ErrClosed after Finish.
+func (w *SpillWriter) Write(buf []byte) (int, error) {
+	if w.finished {
+		return 0, ErrClosed
+	}
+	if w.file == nil && len(w.mem)+len(buf) > w.memLimit {
+		if err := w.spill(); err != nil {
+			return 0, err
+		}
+	}
+	var n int
+	var err error
+	if w.file != nil {
+		n, err = w.file.Write(buf)
+	} else {
+		n = len(buf)
+		w.mem = append(w.mem, buf...)
+	}
+	w.d.Write(buf[:n])
+	w.n += int64(n)
+	return n, err
+}
+
+// spill moves the buffered data to a new temporary file.
+func (w *SpillWriter) spill() error {
+	f, err := os.CreateTemp(w.dir, "blake2b-spill-")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(w.mem); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	w.file = f
+	w.mem = nil
+	return nil
+}
+
+// Finish ends the input and returns its checksum and length, and a
+// reader of the content, which stays valid until Close.
+func (w *SpillWriter) Finish() (digest []byte, length int64, content io.Reader, err error) {
+	if w.finished {
+		return nil, 0, nil, ErrClosed
+	}
+	w.finished = true
+	if w.file == nil {
+		return w.d.Sum(nil), w.n, bytes.NewReader(w.mem), nil
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, nil, err
+	}
+	return w.d.Sum(nil), w.n, w.file, nil
+}
+
+// Close releases the buffered content and removes the temporary file,
+// if any. It may be called with or without a preceding Finish.
+func (w *SpillWriter) Close() error {
+	w.finished = true
+	w.mem = nil
+	if w.file == nil {
+		return nil
+	}
+	f := w.file
+	w.file = nil
+	err := f.Close()
+	if rerr := os.Remove(f.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package blake2b
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func testSpillWriter(t *testing.T, input []byte, memLimit int, spilled bool) {
+	dir := t.TempDir()
+	w := NewSpillWriter(32, memLimit, dir)
+	for rest := input; len(rest) > 0; {
+		n := 1000
+		if n > len(rest) {
+			n = len(rest)
+		}
+		if _, err := w.Write(rest[:n]); err != nil {
+			t.Fatal(err)
+		}
+		rest = rest[n:]
+	}
+	digest, length, content, err := w.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length != int64(len(input)) {
+		t.Errorf("bad length: expected=%d, actual=%d", len(input), length)
+	}
+	if expected := HashAll(input, 32); !bytes.Equal(digest, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, digest)
+	}
+	stored, err := io.ReadAll(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stored, input) {
+		t.Errorf("bad content: %d bytes, expected %d", len(stored), len(input))
+	}
+
+	files, _ := os.ReadDir(dir)
+	if spilled != (len(files) == 1) {
+		t.Errorf("bad spill: expected=%v, files=%d", spilled, len(files))
+	}
+	if _, err := w.Write([]byte("more")); err != ErrClosed {
+		t.Errorf("Write after Finish: expected ErrClosed, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("temporary file left after Close: %d files", len(files))
+	}
+}
+
+func TestSpillWriterSmall(t *testing.T) {
+	testSpillWriter(t, bytes.Repeat([]byte("small "), 100), 4096, false)
+}
+
+func TestSpillWriterLarge(t *testing.T) {
+	input := make([]byte, 100000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	testSpillWriter(t, input, 4096, true)
+}
+
+func TestSpillWriterEmpty(t *testing.T) {
+	testSpillWriter(t, nil, 0, false)
+}
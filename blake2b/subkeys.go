@@ -0,0 +1,60 @@
+package blake2b
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// SubKeys derives n independent subkeys of the given size from master.
+//
+// Subkey i is the keyed Blake2b checksum of the empty input, using master
+// as the key and the personalization "subkeys\x00" followed by i as a
+// little-endian uint64. Each subkey thus comes from a distinct instance
+// of the keyed hash and reveals nothing about master or its siblings.
+func SubKeys(master []byte, n, size int) ([][]byte, error) {
+	if len(master) == 0 {
+		return nil, errors.New("blake2b: empty master key")
+	}
+	if n < 0 {
+		return nil, errors.New("blake2b: negative subkey count")
+	}
+	var personal [PersonalSize]byte
+	copy(personal[:], "subkeys\x00")
+
+	keys := make([][]byte, n)
+	for i := range keys {
+		binary.LittleEndian.PutUint64(personal[8:], uint64(i))
+		h, err := NewWith(WithKey(master), WithSize(size), WithPersonal(personal[:]))
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = h.Sum(nil)
+	}
+	return keys, nil
+}
+
+// DeriveEncAuthKeys derives an encryption key and an authentication key
+// from master, for constructions such as encrypt-then-MAC that must not
+// use one key for both. Each key is the 32-byte keyed Blake2b checksum of
+// the empty input under master, with the personalization "encryption" or
+// "authentication", so neither key reveals the other. It panics if master
+// is empty or longer than KeySize.
+func DeriveEncAuthKeys(master []byte) (encKey, authKey [32]byte) {
+	if len(master) == 0 {
+		panic("blake2b: empty master key")
+	}
+	for _, k := range []struct {
+		personal string
+		key      *[32]byte
+	}{
+		{"encryption", &encKey},
+		{"authentication", &authKey},
+	} {
+		h, err := NewWith(WithKey(master), WithSize(32), WithPersonal([]byte(k.personal)))
+		if err != nil {
+			panic(err)
+		}
+		copy(k.key[:], h.Sum(nil))
+	}
+	return
+}
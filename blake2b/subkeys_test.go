@@ -0,0 +1,96 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSubKeys(t *testing.T) {
+	master := make([]byte, 32)
+	for i := range master {
+		master[i] = byte(i)
+	}
+	expected := []string{
+		"D6631A56DF913BF2D0A5B2EA7B9A15D38194D9B1EB50B0238DEA0B0E5569D417",
+		"D5EB7EF58A9D12E95146EAEE753E1FCC2AB72A70A4C5F071E5EF55AA41DA0B88",
+		"A2A6BC55756DAB71E5E1C0DB22772612E5753FBEBC352C3DF0446B42ED00AA67",
+	}
+
+	keys, err := SubKeys(master, len(expected), 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range keys {
+		if actual := fmt.Sprintf("%X", key); actual != expected[i] {
+			t.Errorf("bad subkey %d: expected=%s, actual=%s", i, expected[i], actual)
+		}
+		for j := 0; j < i; j++ {
+			if bytes.Equal(key, keys[j]) {
+				t.Errorf("subkeys %d and %d are equal", j, i)
+			}
+		}
+	}
+
+	again, _ := SubKeys(master, len(expected), 32)
+	for i := range keys {
+		if !bytes.Equal(keys[i], again[i]) {
+			t.Errorf("subkey %d is not deterministic", i)
+		}
+	}
+
+	master[0] ^= 1
+	changed, _ := SubKeys(master, len(expected), 32)
+	for i := range keys {
+		if bytes.Equal(keys[i], changed[i]) {
+			t.Errorf("subkey %d did not change with the master key", i)
+		}
+	}
+}
+
+func TestSubKeysErrors(t *testing.T) {
+	if _, err := SubKeys(nil, 1, 32); err == nil {
+		t.Error("expected error for empty master key")
+	}
+	if _, err := SubKeys(make([]byte, KeySize+1), 1, 32); err == nil {
+		t.Error("expected error for oversized master key")
+	}
+	if _, err := SubKeys([]byte("master"), 1, Size+1); err == nil {
+		t.Error("expected error for oversized subkey")
+	}
+	if _, err := SubKeys([]byte("master"), -1, 32); err == nil {
+		t.Error("expected error for negative count")
+	}
+}
+
+func TestDeriveEncAuthKeys(t *testing.T) {
+	encKey, authKey := DeriveEncAuthKeys([]byte("master key"))
+	if actual, expected := fmt.Sprintf("%X", encKey), "7102A4FA77239352B20B536EC6BFB05289CF14F87E93DC5BC5212D09814C08C1"; actual != expected {
+		t.Errorf("bad encryption key: expected=%s, actual=%s", expected, actual)
+	}
+	if actual, expected := fmt.Sprintf("%X", authKey), "670AE7CFD105310F0A7F8CEF1C20577527ECBF23B98BE5B5FDD25C3613FEE3F7"; actual != expected {
+		t.Errorf("bad authentication key: expected=%s, actual=%s", expected, actual)
+	}
+	if encKey == authKey {
+		t.Error("encryption and authentication keys are equal")
+	}
+	if enc2, auth2 := DeriveEncAuthKeys([]byte("master key")); enc2 != encKey || auth2 != authKey {
+		t.Error("keys are not deterministic")
+	}
+	if enc2, auth2 := DeriveEncAuthKeys([]byte("master kez")); enc2 == encKey || auth2 == authKey {
+		t.Error("keys did not change with the master key")
+	}
+}
+
+func TestDeriveEncAuthKeysInvalid(t *testing.T) {
+	for _, master := range [][]byte{nil, make([]byte, KeySize+1)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for master key of %d bytes", len(master))
+				}
+			}()
+			DeriveEncAuthKeys(master)
+		}()
+	}
+}
@@ -0,0 +1,186 @@
+package blake2b
+
+import "encoding/binary"
+
+// Sum256And512 returns the Blake2b-256 and Blake2b-512 checksums of data.
+//
+// The digest size is part of the parameter block, so the two checksums
+// share no state: this runs two independent hashes over data, and the
+// 256-bit result is not a truncation of the 512-bit one.
+func Sum256And512(data []byte) (sum256 [32]byte, sum512 [64]byte) {
+	d256 := newDigest()
+	d256.size = 32
+	d256.Reset()
+	d512 := newDigest()
+	d512.Reset()
+
+	// Feed both digests chunk by chunk so data is only streamed once.
+	for len(data) > 0 {
+		n := len(data)
+		if n > readBufSize {
+			n = readBufSize
+		}
+		d256.Write(data[:n])
+		d512.Write(data[:n])
+		data = data[n:]
+	}
+	copy(sum256[:], d256.Sum(nil))
+	copy(sum512[:], d512.Sum(nil))
+	return
+}
+
+// EstimateCompressions returns the number of block compressions needed to
+// hash inputLen bytes, counting the key block if keyed is set. The final
+// block is always compressed, so empty input costs one compression; a
+// keyed hash of empty input compresses only the key block, as final.
+func EstimateCompressions(inputLen int, keyed bool) int {
+	if inputLen < 0 {
+		inputLen = 0
+	}
+	if keyed {
+		inputLen += BlockSize
+	}
+	if inputLen == 0 {
+		return 1
+	}
+	return (inputLen + BlockSize - 1) / BlockSize
+}
+
+// HashAll returns the Blake2b checksum of size bytes of data. It
+// compresses the blocks of data in place and only copies the final block,
+// without the staging buffer of a digest. It panics if size is not
+// between 1 and Size.
+func HashAll(data []byte, size int) []byte {
+	if size < 1 || size > Size {
+		panic("blake2b: invalid digest size")
+	}
+	sum := sumArray(data, size)
+	return append([]byte(nil), sum[:size]...)
+}
+
+// Sum128 returns the 16-byte Blake2b checksum of data.
+func Sum128(data []byte) (sum Digest128) {
+	full := sumArray(data, len(sum))
+	copy(sum[:], full[:])
+	return
+}
+
+// Sum160 returns the 20-byte Blake2b checksum of data.
+func Sum160(data []byte) (sum Digest160) {
+	full := sumArray(data, len(sum))
+	copy(sum[:], full[:])
+	return
+}
+
+// Sum224 returns the 28-byte Blake2b checksum of data.
+func Sum224(data []byte) (sum Digest224) {
+	full := sumArray(data, len(sum))
+	copy(sum[:], full[:])
+	return
+}
+
+// Sum256 returns the 32-byte Blake2b checksum of data.
+func Sum256(data []byte) (sum Digest256) {
+	full := sumArray(data, len(sum))
+	copy(sum[:], full[:])
+	return
+}
+
+// Sum384 returns the 48-byte Blake2b checksum of data.
+func Sum384(data []byte) (sum Digest384) {
+	full := sumArray(data, len(sum))
+	copy(sum[:], full[:])
+	return
+}
+
+// Sum512 returns the 64-byte Blake2b checksum of data.
+func Sum512(data []byte) (sum Digest512) {
+	full := sumArray(data, len(sum))
+	copy(sum[:], full[:])
+	return
+}
+
+// SumBlock returns the size byte Blake2b checksum of the single block
+// block. The block is compressed at once with the final flag set. It is
+// meant for advanced protocols that hash fixed inputs of exactly one
+// block: unlike a digest, which holds a full block back in case more
+// input follows, there is no buffering and no second pass over the data.
+// The checksum equals HashAll(block[:], size). It panics if size is not
+// between 1 and Size.
+func SumBlock(block *[BlockSize]byte, size int) []byte {
+	if size < 1 || size > Size {
+		panic("blake2b: invalid digest size")
+	}
+	h := iv
+	h[0] ^= 0x01010000 | uint64(size)
+	compress(&h, block[:], [2]uint64{BlockSize, 0}, true, false)
+	sum := stateBytes(&h)
+	return sum[:size:size]
+}
+
+// sumArray backs HashAll and the fixed-size helpers. It returns the full
+// chain value of the size byte checksum of data, of which the caller
+// keeps the first size bytes; nothing escapes to the heap. A slice
+// cannot hold 2^64 bytes, so the high counter word stays zero. The
+// caller validates size.
+func sumArray(data []byte, size int) [Size]byte {
+	if len(data) <= BlockSize {
+		return sumShort(nil, data, size)
+	}
+	h := iv
+	h[0] ^= 0x01010000 | uint64(size)
+
+	var t [2]uint64
+	for len(data) > BlockSize {
+		t[0] += BlockSize
+		compress(&h, data, t, false, false)
+		data = data[BlockSize:]
+	}
+	var block [BlockSize]byte
+	copy(block[:], data)
+	t[0] += uint64(len(data))
+	compress(&h, block[:], t, true, false)
+	return stateBytes(&h)
+}
+
+// SumWithLen returns the size byte Blake2b checksum of data together
+// with the length of data, for callers recording both. It panics if size
+// is not between 1 and Size.
+func SumWithLen(data []byte, size int) ([]byte, int) {
+	return HashAll(data, size), len(data)
+}
+
+// sumShort is the fast path of the one-shot helpers for messages of at
+// most BlockSize bytes: it derives the chain value straight from the
+// parameter block and compresses the optional key block and data from a
+// local block, without setting up a digest and its staging buffer. The
+// caller validates size and the lengths of key and data.
+func sumShort(key, data []byte, size int) (sum [Size]byte) {
+	h := iv
+	h[0] ^= 0x01010000 | uint64(len(key))<<8 | uint64(size)
+
+	var block [BlockSize]byte
+	var t [2]uint64
+	if len(key) > 0 {
+		copy(block[:], key)
+		t[0] = BlockSize
+		if len(data) == 0 {
+			compress(&h, block[:], t, true, false)
+			return stateBytes(&h)
+		}
+		compress(&h, block[:], t, false, false)
+		block = [BlockSize]byte{}
+	}
+	copy(block[:], data)
+	t[0] += uint64(len(data))
+	compress(&h, block[:], t, true, false)
+	return stateBytes(&h)
+}
+
+// stateBytes serializes the chain value h in little endian order.
+func stateBytes(h *[8]uint64) (out [Size]byte) {
+	for i := 0; i < 8; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], h[i])
+	}
+	return out
+}
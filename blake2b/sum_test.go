@@ -0,0 +1,178 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"testing"
+)
+
+func TestSum256And512(t *testing.T) {
+	input := bytes.Repeat([]byte("one two three "), 5000)
+	sum256, sum512 := Sum256And512(input)
+
+	h, _ := NewWith(WithSize(32))
+	h.Write(input)
+	if expected := h.Sum(nil); !bytes.Equal(sum256[:], expected) {
+		t.Errorf("bad 256-bit hash: expected=%X, actual=%X", expected, sum256)
+	}
+	h = New()
+	h.Write(input)
+	if expected := h.Sum(nil); !bytes.Equal(sum512[:], expected) {
+		t.Errorf("bad 512-bit hash: expected=%X, actual=%X", expected, sum512)
+	}
+	if bytes.Equal(sum256[:], sum512[:32]) {
+		t.Error("256-bit hash is a truncation of the 512-bit hash")
+	}
+}
+
+func TestEstimateCompressions(t *testing.T) {
+	for _, v := range []struct {
+		inputLen int
+		keyed    bool
+		expected int
+	}{
+		{0, false, 1},
+		{1, false, 1},
+		{BlockSize, false, 1},
+		{BlockSize + 1, false, 2},
+		{10 * BlockSize, false, 10},
+		// Keyed mode: the key block comes first.
+		{0, true, 1},
+		{1, true, 2},
+		{BlockSize, true, 2},
+		{BlockSize + 1, true, 3},
+	} {
+		if actual := EstimateCompressions(v.inputLen, v.keyed); actual != v.expected {
+			t.Errorf("bad estimate (%d, keyed %v): expected=%d, actual=%d", v.inputLen, v.keyed, v.expected, actual)
+		}
+	}
+}
+
+// TestEstimateCompressionsCount checks the estimate against the work of
+// real digests: every block compressed by Write advances the counter by
+// BlockSize, and Sum compresses the staged bytes, at least one block.
+func TestEstimateCompressionsCount(t *testing.T) {
+	input := make([]byte, 5*BlockSize)
+	for _, keyed := range []bool{false, true} {
+		for n := 0; n <= len(input); n++ {
+			var h hash.Hash = New()
+			if keyed {
+				h = NewKeyed([]byte("key"))
+			}
+			h.Write(input[:n])
+			d := h.(*digest)
+			count := int(d.t[0]/BlockSize) + (d.buflen+BlockSize-1)/BlockSize
+			if d.buflen == 0 {
+				count++
+			}
+			if expected := EstimateCompressions(n, keyed); count != expected {
+				t.Errorf("bad estimate (%d, keyed %v): estimated=%d, compressed=%d", n, keyed, expected, count)
+			}
+		}
+	}
+}
+
+func TestHashAll(t *testing.T) {
+	input := make([]byte, 1000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	for _, size := range []int{1, 32, Size} {
+		for _, n := range []int{0, 1, BlockSize - 1, BlockSize, BlockSize + 1, 2 * BlockSize, 1000} {
+			h, _ := NewWith(WithSize(size))
+			h.Write(input[:n])
+			if actual, expected := HashAll(input[:n], size), h.Sum(nil); !bytes.Equal(actual, expected) {
+				t.Errorf("bad hash (size %d, len %d): expected=%X, actual=%X", size, n, expected, actual)
+			}
+		}
+	}
+}
+
+func TestSingleBlockVectors(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for n := 0; n <= BlockSize+1; n++ {
+		input := make([]byte, n)
+		for i := range input {
+			input[i] = byte(i)
+		}
+		if actual := fmt.Sprintf("%X", HashAll(input, Size)); actual != unkeyed2b[n] {
+			t.Errorf("bad hash (%d): expected=%s, actual=%s", n, unkeyed2b[n], actual)
+		}
+		if actual := fmt.Sprintf("%X", MAC(key, input, Size)); actual != keyed2B[n] {
+			t.Errorf("bad keyed hash (%d): expected=%s, actual=%s", n, keyed2B[n], actual)
+		}
+	}
+}
+
+func TestSumWithLen(t *testing.T) {
+	input := bytes.Repeat([]byte("abc"), 100)
+	sum, n := SumWithLen(input, 32)
+	if n != len(input) {
+		t.Errorf("bad length: expected=%d, actual=%d", len(input), n)
+	}
+	if expected := HashAll(input, 32); !bytes.Equal(sum, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, sum)
+	}
+}
+
+func TestFixedSizeSums(t *testing.T) {
+	input := make([]byte, 300)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	for _, v := range []struct {
+		size     int
+		sum      func([]byte) []byte
+		expected string
+	}{
+		{16, func(b []byte) []byte { s := Sum128(b); return s[:] }, "A021A719DABBBCE707BEC49E7A6C9865"},
+		{20, func(b []byte) []byte { s := Sum160(b); return s[:] }, "65E05D1CECBB370304BC5213F53B9B0093208AEA"},
+		{28, func(b []byte) []byte { s := Sum224(b); return s[:] }, "EA5EB12D32EFD9FF01BB4B6A09C7E4A00D898B5D6629311C57BBA023"},
+		{32, func(b []byte) []byte { s := Sum256(b); return s[:] }, "940563F11807C8BA3192299E05CF544B82463742C8A5E80C2A5D81751CD8B0CA"},
+		{48, func(b []byte) []byte { s := Sum384(b); return s[:] }, "8BABFE78D19949E35B6D53AFCA26E4E3020A44DCF304670AF4C47579D01AF707B922CBB9CD85078470E31E264F9F9726"},
+		{64, func(b []byte) []byte { s := Sum512(b); return s[:] }, "3A482B7748B0BDC43C3D00C080890C10E57A9AA5618F78B86067EB7EAAE4942ACD96D827ACCBC16958364AE5B0DF6105BBD3B15445092EBA1137B5F69C1070F1"},
+	} {
+		if actual := fmt.Sprintf("%X", v.sum(input)); actual != v.expected {
+			t.Errorf("bad hash (%d): expected=%s, actual=%s", v.size, v.expected, actual)
+		}
+		if actual, expected := v.sum(input[:10]), HashAll(input[:10], v.size); !bytes.Equal(actual, expected) {
+			t.Errorf("bad short hash (%d): expected=%X, actual=%X", v.size, expected, actual)
+		}
+	}
+
+	sum := Sum256(nil)
+	expected := "0E5751C026E543B2E8AB2EB06099DAA1D1E5DF47778F7787FAAB45CDF12FE3A8"
+	if actual := fmt.Sprintf("%X", sum); actual != expected {
+		t.Errorf("bad empty hash: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestFixedSizeSumsAllocs(t *testing.T) {
+	input := make([]byte, 1000)
+	if n := testing.AllocsPerRun(10, func() { Sum256(input) }); n != 0 {
+		t.Errorf("Sum256 allocated %v times", n)
+	}
+}
+
+func TestSumBlock(t *testing.T) {
+	var block [BlockSize]byte
+	for i := range block {
+		block[i] = byte(i % 251)
+	}
+	sum := SumBlock(&block, 32)
+	actual := fmt.Sprintf("%X", sum)
+	expected := "C3582F71EBB2BE66FA5DD750F80BAAE97554F3B015663C8BE377CFCB2488C1D1"
+	if actual != expected {
+		t.Errorf("bad hash: expected=%s, actual=%s", expected, actual)
+	}
+	for _, size := range []int{1, 20, Size} {
+		sum := SumBlock(&block, size)
+		if expected := HashAll(block[:], size); !bytes.Equal(sum, expected) {
+			t.Errorf("bad hash (%d): expected=%X, actual=%X", size, expected, sum)
+		}
+	}
+}
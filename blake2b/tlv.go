@@ -0,0 +1,30 @@
+package blake2b
+
+import "encoding/binary"
+
+// A TLV is one type-length-value item hashed by HashTLV.
+type TLV struct {
+	Type  uint8
+	Value []byte
+}
+
+// HashTLV returns the Blake2b checksum of size bytes of items, each
+// encoded as its type byte, the uvarint length of its value and the
+// value. The encoding is unambiguous, so splitting, merging or
+// reordering items changes the digest, and an item with an empty value
+// still contributes its type. It panics if size is not between 1 and
+// Size.
+func HashTLV(items []TLV, size int) []byte {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		panic(err)
+	}
+	var header [1 + binary.MaxVarintLen64]byte
+	for _, item := range items {
+		header[0] = item.Type
+		n := binary.PutUvarint(header[1:], uint64(len(item.Value)))
+		h.Write(header[:1+n])
+		h.Write(item.Value)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,43 @@
+package blake2b
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHashTLV(t *testing.T) {
+	items := []TLV{{1, []byte("name")}, {2, []byte("value")}, {3, nil}}
+	sum := HashTLV(items, 32)
+
+	input := []byte{1, 4, 'n', 'a', 'm', 'e', 2, 5, 'v', 'a', 'l', 'u', 'e', 3, 0}
+	if expected := HashAll(input, 32); !bytes.Equal(sum, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, sum)
+	}
+
+	reordered := []TLV{items[1], items[0], items[2]}
+	if bytes.Equal(HashTLV(reordered, 32), sum) {
+		t.Error("reordering items did not change the hash")
+	}
+	if bytes.Equal(HashTLV(items[:2], 32), sum) {
+		t.Error("dropping an empty item did not change the hash")
+	}
+	if !bytes.Equal(HashTLV([]TLV{{1, []byte("name")}, {2, []byte("value")}, {3, []byte{}}}, 32), sum) {
+		t.Error("nil and empty values hash differently")
+	}
+}
+
+func TestHashTLVFraming(t *testing.T) {
+	for _, items := range [][]TLV{
+		{{1, []byte("ab")}},
+		{{1, []byte("a")}, {1, []byte("b")}},
+		{{1, nil}, {1, []byte("ab")}},
+		{{2, []byte("ab")}},
+	} {
+		if bytes.Equal(HashTLV(items, 32), HashTLV([]TLV{{1, []byte("ab")}}, 32)) != (len(items) == 1 && items[0].Type == 1) {
+			t.Errorf("ambiguous encoding of %v", items)
+		}
+	}
+	if actual := HashTLV(nil, 32); !bytes.Equal(actual, HashAll(nil, 32)) {
+		t.Errorf("bad hash of no items: %X", actual)
+	}
+}
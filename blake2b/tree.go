@@ -0,0 +1,116 @@
+package blake2b
+
+import "hash"
+
+// Tree describes the shape of a Blake2b hash tree, as defined in section
+// 2.10 of https://blake2.net/blake2_20130129.pdf.
+//
+// Every node of a tree is hashed with the same fanout, maximal depth and
+// leaf size in its parameter block; nodes only differ in their node
+// offset, node depth and last node flag. The caller is responsible for
+// splitting the input into leaves and for feeding the digests of the
+// children of a node, in order, into the hasher of that node.
+type Tree struct {
+	// Fanout is the maximal number of children of a node, 0 for unlimited.
+	Fanout uint8
+
+	// MaxDepth is the height of the tree. A tree with leaves and a root
+	// has a MaxDepth of 2.
+	MaxDepth uint8
+
+	// LeafSize is the maximal byte length of a leaf, 0 for unlimited.
+	LeafSize uint32
+
+	// InnerSize is the digest size of the leaves and inner nodes, at most
+	// Size. The root always emits a Size byte digest. 0 means Size.
+	InnerSize uint8
+}
+
+// defaultTree is a two-level tree with unlimited fanout and leaf size.
+var defaultTree = Tree{Fanout: 0, MaxDepth: 2}
+
+// NodeHasher returns a new hash.Hash computing the Blake2b checksum of
+// the node at the given depth and offset. The depth of a leaf is 0, and
+// last must be set for the rightmost node of each level.
+func (t Tree) NodeHasher(depth uint8, offset uint64, last bool) hash.Hash {
+	if t.MaxDepth == 0 || depth >= t.MaxDepth {
+		panic("blake2b: invalid tree node depth")
+	}
+	innerSize := int(t.InnerSize)
+	if innerSize == 0 {
+		innerSize = Size
+	}
+	if innerSize > Size {
+		panic("blake2b: invalid tree inner hash size")
+	}
+	d := newDigest()
+	if depth < t.MaxDepth-1 {
+		d.size = innerSize
+	}
+	d.fanout = t.Fanout
+	d.depth = t.MaxDepth
+	d.leafSize = t.LeafSize
+	d.nodeOffset = offset
+	d.nodeDepth = depth
+	d.innerSize = uint8(innerSize)
+	d.lastNode = last
+	d.Reset()
+	return d
+}
+
+// LeafHasher returns a new hash.Hash computing the Blake2b checksum of
+// the leaf at the given offset.
+func (t Tree) LeafHasher(offset uint64, last bool) hash.Hash {
+	return t.NodeHasher(0, offset, last)
+}
+
+// RootHasher returns a new hash.Hash computing the Blake2b checksum of
+// the root node, which sits at depth MaxDepth-1.
+func (t Tree) RootHasher() hash.Hash {
+	return t.NodeHasher(t.MaxDepth-1, 0, true)
+}
+
+// LeafHasher returns a new hash.Hash computing the Blake2b checksum of
+// a leaf of a two-level tree with unlimited fanout and leaf size.
+func LeafHasher(offset uint64, last bool) hash.Hash {
+	return defaultTree.LeafHasher(offset, last)
+}
+
+// RootHasher returns a new hash.Hash computing the Blake2b checksum of
+// the root of a two-level tree with unlimited fanout and leaf size.
+func RootHasher() hash.Hash {
+	return defaultTree.RootHasher()
+}
+
+// combineTree is the two-leaf tree joined by CombineRoots: its nodes use
+// the fanout 2, depth 2 and Size byte inner digests of the Blake2bp root
+// construction, with unlimited leaf size.
+var combineTree = Tree{Fanout: 2, MaxDepth: 2}
+
+// CombineLeafHasher returns a new hash.Hash computing the Blake2b
+// checksum of the left or right leaf joined by CombineRoots.
+func CombineLeafHasher(right bool) hash.Hash {
+	if right {
+		return combineTree.LeafHasher(1, true)
+	}
+	return combineTree.LeafHasher(0, false)
+}
+
+// CombineRoots returns the root digest over the leaf digests left and
+// right, as computed by CombineLeafHasher(false) and
+// CombineLeafHasher(true). The leaves are nodes of a fanout 2, depth 2
+// tree with the right leaf flagged as last node; digests of any other
+// node parameters, including plain Blake2b digests, yield an unrelated
+// root. This lets two workers each hash one contiguous half of an input.
+// The result differs from the Blake2bp digest of the same input, which
+// interleaves its leaves block by block. It panics if either digest is
+// not Size bytes.
+func CombineRoots(left, right []byte) []byte {
+	if len(left) != Size || len(right) != Size {
+		panic("blake2b: invalid leaf digest size")
+	}
+	root := combineTree.RootHasher()
+	root.Write(left)
+	root.Write(right)
+	return root.Sum(nil)
+}
@@ -0,0 +1,119 @@
+package blake2b
+
+import (
+	"fmt"
+	"hash"
+	"testing"
+)
+
+func treeInput() []byte {
+	input := make([]byte, 300)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	return input
+}
+
+func treeRoot(root hash.Hash, leaves []hash.Hash, chunks [][]byte) string {
+	for i, leaf := range leaves {
+		leaf.Write(chunks[i])
+		root.Write(leaf.Sum(nil))
+	}
+	return fmt.Sprintf("%X", root.Sum(nil))
+}
+
+func TestTwoLeafTree(t *testing.T) {
+	input := treeInput()
+	leaves := []hash.Hash{LeafHasher(0, false), LeafHasher(1, true)}
+	actual := treeRoot(RootHasher(), leaves, [][]byte{input[:150], input[150:]})
+
+	expected := "B610BF918B0F7BA39AD6954808143BCA4DDECA95BFDC7E67FC6A73270C2A2BAA1512022E6ED91380A5D2EAEC215A0AFE335732678A62656FABA2D2D0F950C348"
+	if actual != expected {
+		t.Errorf("bad root: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestThreeLeafTree(t *testing.T) {
+	input := treeInput()
+	tree := Tree{Fanout: 3, MaxDepth: 2, LeafSize: 128}
+	leaves := []hash.Hash{
+		tree.LeafHasher(0, false),
+		tree.LeafHasher(1, false),
+		tree.LeafHasher(2, true),
+	}
+	actual := treeRoot(tree.RootHasher(), leaves, [][]byte{input[:128], input[128:256], input[256:]})
+
+	expected := "9D5AEDDC4C761730062547B6AEBB1396C87F7253C88A7FE39E3A1D6E37D20FE03BDF76C7C173EF2DC761CEBF5163D8FE7CF7736D85D2F3AC98D612EB3E90D152"
+	if actual != expected {
+		t.Errorf("bad root: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestLeafHasher(t *testing.T) {
+	h := LeafHasher(0, false)
+	h.Write(treeInput()[:150])
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+
+	expected := "6F304F0E465CB3F15B03117AF4634CE73ADB167D21B9F1B70950BD632C67A3C226CE0A2F158E97B19EC6BB24303F3BB9A261245B52CEDE82AAF25B315CD2849B"
+	if actual != expected {
+		t.Errorf("bad leaf: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestTreeInnerSize(t *testing.T) {
+	input := treeInput()
+	tree := Tree{Fanout: 2, MaxDepth: 2, InnerSize: 32}
+	leaves := []hash.Hash{tree.LeafHasher(0, false), tree.LeafHasher(1, true)}
+	if size := leaves[0].Size(); size != 32 {
+		t.Errorf("bad leaf size: %d", size)
+	}
+	root := tree.RootHasher()
+	if size := root.Size(); size != Size {
+		t.Errorf("bad root size: %d", size)
+	}
+	actual := treeRoot(root, leaves, [][]byte{input[:150], input[150:]})
+
+	expected := "66E5D07CA8C2C0898643459AB831B6D1846A103790290A53949BC443F71A31B22911399E253B8B1758CBBB30DC4E5E562F8EC5D1C2F2B2B8FFFBC671E157420C"
+	if actual != expected {
+		t.Errorf("bad root: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestTreeInnerSizeInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for oversized inner hash")
+		}
+	}()
+	Tree{MaxDepth: 2, InnerSize: Size + 1}.LeafHasher(0, true)
+}
+
+func TestCombineRoots(t *testing.T) {
+	input := treeInput()
+	left := CombineLeafHasher(false)
+	left.Write(input[:150])
+	right := CombineLeafHasher(true)
+	right.Write(input[150:])
+	actual := fmt.Sprintf("%X", CombineRoots(left.Sum(nil), right.Sum(nil)))
+
+	// Computed with an independent Blake2b reference implementation.
+	expected := "138FC0C7B8F80B1BDC8F7F20A13C43FA786E3DDB1B5BD412FAEF475B9AEA40988BBCFE520DBC2CC7B76CE63A890AEB8EDDAEDB4AB7F8D504882A8F801D13FCA7"
+	if actual != expected {
+		t.Errorf("bad root: expected=%s, actual=%s", expected, actual)
+	}
+
+	// The same root, built node by node from the tree parameters.
+	tree := Tree{Fanout: 2, MaxDepth: 2}
+	leaves := []hash.Hash{tree.NodeHasher(0, 0, false), tree.NodeHasher(0, 1, true)}
+	if byHand := treeRoot(tree.NodeHasher(1, 0, true), leaves, [][]byte{input[:150], input[150:]}); actual != byHand {
+		t.Errorf("bad root: expected=%s, actual=%s", byHand, actual)
+	}
+
+	// Blake2bp interleaves its leaves block by block, so its digest of
+	// the same input is necessarily different.
+	p := NewP()
+	p.Write(input)
+	if bp := fmt.Sprintf("%X", p.Sum(nil)); actual == bp {
+		t.Errorf("root equals the Blake2bp digest %s", bp)
+	}
+}
@@ -0,0 +1,30 @@
+package blake2b
+
+import (
+	"bytes"
+	"sort"
+)
+
+// CombineUnordered returns the Blake2b checksum of the multiset of
+// digests: the digests are sorted and hashed, each preceded by its
+// uvarint length as with NewPrefixed, so the result does not depend on
+// their order but does on how often each occurs. digests is not modified.
+//
+// Sorting was chosen over XORing the digests, which is cheaper and can be
+// updated incrementally but lets equal elements cancel out and lets an
+// attacker who can choose elements reach any target by solving a linear
+// system over GF(2). With sorting, finding two different multisets with
+// the same result is as hard as finding a Blake2b collision, at the cost
+// of O(n log n) time and a copy of the slice headers. The elements should
+// be digests themselves, so that their encoding does not matter.
+func CombineUnordered(digests [][]byte) []byte {
+	sorted := append([][]byte(nil), digests...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	p := NewPrefixed()
+	for _, d := range sorted {
+		p.Write(d)
+	}
+	return p.Sum(nil)
+}
@@ -0,0 +1,53 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func unorderedInput(n int) [][]byte {
+	digests := make([][]byte, n)
+	for i := range digests {
+		digests[i] = HashAll([]byte(fmt.Sprint(i)), 32)
+	}
+	return digests
+}
+
+func TestCombineUnordered(t *testing.T) {
+	digests := unorderedInput(20)
+	sum := CombineUnordered(digests)
+
+	reversed := make([][]byte, len(digests))
+	for i, d := range digests {
+		reversed[len(digests)-1-i] = d
+	}
+	if actual := CombineUnordered(reversed); !bytes.Equal(actual, sum) {
+		t.Errorf("order changed the hash: expected=%X, actual=%X", sum, actual)
+	}
+	rotated := append(append([][]byte(nil), digests[7:]...), digests[:7]...)
+	if actual := CombineUnordered(rotated); !bytes.Equal(actual, sum) {
+		t.Errorf("order changed the hash: expected=%X, actual=%X", sum, actual)
+	}
+	if !bytes.Equal(digests[0], HashAll([]byte("0"), 32)) {
+		t.Error("CombineUnordered modified its input")
+	}
+}
+
+func TestCombineUnorderedChanges(t *testing.T) {
+	digests := unorderedInput(20)
+	sum := CombineUnordered(digests)
+
+	changed := unorderedInput(20)
+	changed[5] = HashAll([]byte("other"), 32)
+	if bytes.Equal(CombineUnordered(changed), sum) {
+		t.Error("changed element did not change the hash")
+	}
+	if bytes.Equal(CombineUnordered(digests[1:]), sum) {
+		t.Error("removed element did not change the hash")
+	}
+	duplicated := append(unorderedInput(20), digests[3], digests[3])
+	if bytes.Equal(CombineUnordered(duplicated), sum) {
+		t.Error("duplicated elements canceled out")
+	}
+}
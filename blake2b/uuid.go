@@ -0,0 +1,25 @@
+package blake2b
+
+import "encoding/binary"
+
+// UUIDv8 returns a name-based RFC 4122 version 8 UUID: the 16-byte
+// Blake2b checksum of the uvarint length of namespace, namespace and
+// name, with the version and variant bits set. The length prefix keeps a
+// namespace from running into the name, so different namespaces give
+// different UUIDs for any names. namespace is normally itself a UUID,
+// such as the RFC 4122 DNS or URL namespace.
+func UUIDv8(namespace, name []byte) [16]byte {
+	d := newDigest()
+	d.size = 16
+	d.Reset()
+	var length [binary.MaxVarintLen64]byte
+	d.Write(length[:binary.PutUvarint(length[:], uint64(len(namespace)))])
+	d.Write(namespace)
+	d.Write(name)
+
+	var uuid [16]byte
+	copy(uuid[:], d.Sum(nil))
+	uuid[6] = uuid[6]&0x0f | 0x80 // version 8
+	uuid[8] = uuid[8]&0x3f | 0x80 // RFC 4122 variant
+	return uuid
+}
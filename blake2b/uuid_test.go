@@ -0,0 +1,44 @@
+package blake2b
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+var (
+	dnsNamespace, _ = hex.DecodeString("6ba7b8109dad11d180b400c04fd430c8")
+	urlNamespace, _ = hex.DecodeString("6ba7b8119dad11d180b400c04fd430c8")
+)
+
+func TestUUIDv8(t *testing.T) {
+	uuid := UUIDv8(dnsNamespace, []byte("example.com"))
+	expected := "2d96958a485d8749baa50275e4189a7f"
+	if actual := hex.EncodeToString(uuid[:]); actual != expected {
+		t.Errorf("bad UUID: expected=%s, actual=%s", expected, actual)
+	}
+	if again := UUIDv8(dnsNamespace, []byte("example.com")); again != uuid {
+		t.Error("UUIDv8 is not deterministic")
+	}
+	if other := UUIDv8(urlNamespace, []byte("example.com")); other == uuid {
+		t.Error("different namespaces produced the same UUID")
+	}
+
+	for _, name := range []string{"", "a", "example.org", "another name"} {
+		uuid := UUIDv8(dnsNamespace, []byte(name))
+		if version := uuid[6] >> 4; version != 8 {
+			t.Errorf("bad version (%q): %d", name, version)
+		}
+		if variant := uuid[8] >> 6; variant != 2 {
+			t.Errorf("bad variant (%q): %b", name, variant)
+		}
+	}
+}
+
+func TestUUIDv8Framing(t *testing.T) {
+	if UUIDv8([]byte("ab"), []byte("c")) == UUIDv8([]byte("a"), []byte("bc")) {
+		t.Error("namespace ran into the name")
+	}
+	if UUIDv8(nil, []byte("abc")) == UUIDv8([]byte("abc"), nil) {
+		t.Error("empty namespace and empty name collide")
+	}
+}
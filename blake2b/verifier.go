@@ -0,0 +1,31 @@
+package blake2b
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+)
+
+// ErrDigestMismatch is returned by the finalizer of NewVerifier when the
+// data written does not hash to the expected digest.
+var ErrDigestMismatch = errors.New("blake2b: digest mismatch")
+
+// NewVerifier returns a writer that hashes the data written to it, and a
+// finalizer that compares the checksum with expected in constant time.
+// The length of expected selects the checksum size. The finalizer
+// returns nil on a match and ErrDigestMismatch otherwise; if expected is
+// not between 1 and Size bytes long, the writer discards its input and
+// the finalizer reports the invalid size.
+func NewVerifier(expected []byte) (io.Writer, func() error) {
+	h, err := NewWith(WithSize(len(expected)))
+	if err != nil {
+		return io.Discard, func() error { return err }
+	}
+	expected = append([]byte(nil), expected...)
+	return h, func() error {
+		if subtle.ConstantTimeCompare(h.Sum(nil), expected) != 1 {
+			return ErrDigestMismatch
+		}
+		return nil
+	}
+}
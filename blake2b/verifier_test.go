@@ -0,0 +1,43 @@
+package blake2b
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestVerifier(t *testing.T) {
+	input := strings.Repeat("verify me ", 100)
+	expected := HashAll([]byte(input), 32)
+
+	w, verify := NewVerifier(expected)
+	io.Copy(w, strings.NewReader(input))
+	if err := verify(); err != nil {
+		t.Errorf("matching data: %v", err)
+	}
+
+	w, verify = NewVerifier(expected)
+	io.WriteString(w, input[1:])
+	if err := verify(); err != ErrDigestMismatch {
+		t.Errorf("mismatching data: expected=%v, actual=%v", ErrDigestMismatch, err)
+	}
+
+	// A shorter digest is not a prefix of the longer one.
+	w, verify = NewVerifier(expected[:16])
+	io.WriteString(w, input)
+	if err := verify(); err != ErrDigestMismatch {
+		t.Errorf("truncated digest: expected=%v, actual=%v", ErrDigestMismatch, err)
+	}
+}
+
+func TestVerifierInvalidSize(t *testing.T) {
+	for _, expected := range [][]byte{nil, make([]byte, Size+1)} {
+		w, verify := NewVerifier(expected)
+		if _, err := io.WriteString(w, "data"); err != nil {
+			t.Errorf("Write (%d): %v", len(expected), err)
+		}
+		if err := verify(); err == nil || err == ErrDigestMismatch {
+			t.Errorf("expected size error (%d), got %v", len(expected), err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package blake2b
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrClosed is returned when writing to or closing a closed writer.
+var ErrClosed = errors.New("blake2b: writer closed")
+
+type writeCloser struct {
+	d   *digest
+	sum []byte
+}
+
+// NewWriteCloser returns an io.WriteCloser computing the Blake2b checksum
+// of size bytes of the data written to it. Close finalizes the checksum,
+// which the returned function yields afterwards; before Close it returns
+// nil. It panics if size is not between 1 and Size.
+func NewWriteCloser(size int) (io.WriteCloser, func() []byte) {
+	h, err := NewWith(WithSize(size))
+	if err != nil {
+		panic(err)
+	}
+	w := &writeCloser{d: h.(*digest)}
+	return w, func() []byte { return w.sum }
+}
+
+func (w *writeCloser) Write(buf []byte) (int, error) {
+	if w.sum != nil {
+		return 0, ErrClosed
+	}
+	return w.d.Write(buf)
+}
+
+func (w *writeCloser) Close() error {
+	if w.sum != nil {
+		return ErrClosed
+	}
+	w.sum = w.d.Sum(nil)
+	return nil
+}
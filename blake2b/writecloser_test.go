@@ -0,0 +1,37 @@
+package blake2b
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteCloser(t *testing.T) {
+	w, sum := NewWriteCloser(32)
+	if sum() != nil {
+		t.Error("checksum available before Close")
+	}
+	if _, err := io.Copy(w, strings.NewReader("one two three")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	h, _ := NewWith(WithSize(32))
+	h.Write([]byte("one two three"))
+	if expected := h.Sum(nil); !bytes.Equal(sum(), expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, sum())
+	}
+
+	if err := w.Close(); err != ErrClosed {
+		t.Errorf("double Close: expected ErrClosed, got %v", err)
+	}
+	if n, err := w.Write([]byte("more")); n != 0 || err != ErrClosed {
+		t.Errorf("Write after Close: n=%d, err=%v", n, err)
+	}
+	if expected := h.Sum(nil); !bytes.Equal(sum(), expected) {
+		t.Error("checksum changed after Close")
+	}
+}
@@ -0,0 +1,64 @@
+//go:build xcrypto
+
+// Cross-checks against golang.org/x/crypto/blake2b over random inputs,
+// digest sizes and keys, including empty ones. The repository has no
+// go.mod, so the test builds in GOPATH mode, with x/crypto and the
+// x/sys it imports checked out under GOPATH:
+//
+//	git clone https://go.googlesource.com/crypto $GOPATH/src/golang.org/x/crypto
+//	git clone https://go.googlesource.com/sys $GOPATH/src/golang.org/x/sys
+//	GO111MODULE=off go test -tags xcrypto
+//
+// The two packages differ intentionally in one respect: x/crypto rejects
+// keys longer than KeySize, while NewKeyed truncates them. Only keys of
+// at most KeySize bytes are compared here.
+
+package blake2b
+
+import (
+	"bytes"
+	"hash"
+	"math/rand"
+	"testing"
+
+	xblake2b "golang.org/x/crypto/blake2b"
+)
+
+func TestXCrypto(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		input := make([]byte, rng.Intn(4*BlockSize+1))
+		rng.Read(input)
+
+		h := New()
+		h.Write(input)
+		expected := xblake2b.Sum512(input)
+		if actual := h.Sum(nil); !bytes.Equal(actual, expected[:]) {
+			t.Fatalf("bad hash (%d): input=%X, expected=%X, actual=%X", len(input), input, expected, actual)
+		}
+
+		size := 1 + rng.Intn(Size)
+		key := make([]byte, rng.Intn(KeySize+1))
+		rng.Read(key)
+		x, err := xblake2b.New(size, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x.Write(input)
+		h, err = NewWith(WithSize(size), WithKey(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Write(input)
+		if actual, expected := h.Sum(nil), x.Sum(nil); !bytes.Equal(actual, expected) {
+			t.Fatalf("bad hash (%d, size %d): key=%X, expected=%X, actual=%X", len(input), size, key, expected, actual)
+		}
+	}
+}
+
+func BenchmarkXCryptoBlake2b(b *testing.B) {
+	benchmarkHash(b, func() hash.Hash {
+		h, _ := xblake2b.New512(nil)
+		return h
+	})
+}
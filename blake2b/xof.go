@@ -0,0 +1,241 @@
+package blake2b
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// OutputLengthUnknown can be passed to NewXOF to request an output
+// stream whose length is not fixed in advance. Such a stream ends after
+// 2^32 blocks of Size bytes, 256 GiB.
+const OutputLengthUnknown = 1<<32 - 1
+
+// maxUnknownOutput is the length of an output stream of unknown length.
+const maxUnknownOutput = 1 << 32 * Size
+
+// errWriteAfterRead is the panic value of a Write to an XOF after Read.
+var errWriteAfterRead = errors.New("blake2b: write to XOF after read")
+
+// errXOFState is returned by UnmarshalBinary for malformed input.
+var errXOFState = errors.New("blake2b: invalid XOF state")
+
+const (
+	xofMagic     = "b2X\x01"
+	xofStateSize = len(xofMagic) + 4 + 1 + 1 + KeySize + 8*8 + 2*8 + 2 + 2*BlockSize + 2*Size + 1 + 4 + 8
+)
+
+// XOF is a BLAKE2Xb extendable output function, as defined in
+// https://blake2.net/blake2x.pdf.
+//
+// The input is written first and hashed into a root digest with the
+// output length in its parameter block. Read then expands the root
+// digest into output blocks of Size bytes, each hashed with its block
+// index as node offset. Different output lengths thus give unrelated
+// streams. An XOF is not safe for concurrent use.
+type XOF struct {
+	d      digest
+	length uint32
+
+	// Output state, set up by the first Read.
+	reading    bool
+	h0         [Size]byte
+	block      [Size]byte
+	offset     int
+	nodeOffset uint32
+	remaining  uint64
+}
+
+// NewXOF returns a new XOF producing length bytes of output, or an
+// output stream of unknown length if length is OutputLengthUnknown.
+func NewXOF(length uint32) *XOF {
+	x := &XOF{d: *newDigest(), length: length}
+	x.Reset()
+	return x
+}
+
+// NewKeyedXOF is like NewXOF but keys the root digest with key, which
+// is absorbed as the first input block. Every output block depends on
+// the root digest, so the key changes the whole output stream. It panics
+// if key is longer than KeySize.
+func NewKeyedXOF(length uint32, key []byte) *XOF {
+	if len(key) > KeySize {
+		panic("blake2b: invalid key size")
+	}
+	x := &XOF{d: *newDigest(), length: length}
+	x.d.key = append([]byte(nil), key...)
+	x.Reset()
+	return x
+}
+
+// XOFSum fills out with the BLAKE2Xb output of data with an output
+// length of len(out), keyed with key if it is not empty. Like any XOF
+// output length, len(out) is part of the result: out is not a prefix of
+// a longer output for the same data. An empty out is left alone. It
+// panics if key is longer than KeySize or if out is not shorter than
+// OutputLengthUnknown.
+func XOFSum(data, key, out []byte) {
+	if len(key) > KeySize {
+		panic("blake2b: invalid key size")
+	}
+	if uint64(len(out)) >= OutputLengthUnknown {
+		panic("blake2b: invalid XOF output length")
+	}
+	if len(out) == 0 {
+		return
+	}
+	x := NewKeyedXOF(uint32(len(out)), key)
+	x.Write(data)
+	x.Read(out)
+}
+
+// Reset discards the input and the output position.
+func (x *XOF) Reset() {
+	x.d.nodeOffset = uint64(x.length) << 32
+	x.d.Reset()
+	x.reading = false
+	x.offset = Size
+	x.nodeOffset = 0
+	if x.length == OutputLengthUnknown {
+		x.remaining = maxUnknownOutput
+	} else {
+		x.remaining = uint64(x.length)
+	}
+}
+
+// Write absorbs p into the root digest. It panics if called after Read.
+func (x *XOF) Write(p []byte) (int, error) {
+	if x.reading {
+		panic(errWriteAfterRead)
+	}
+	return x.d.Write(p)
+}
+
+// Read reads the next len(p) bytes of output. It returns io.EOF once
+// the output length is exhausted.
+func (x *XOF) Read(p []byte) (int, error) {
+	if !x.reading {
+		x.reading = true
+		x.h0 = x.d.checkSum()
+	}
+	if x.remaining == 0 {
+		return 0, io.EOF
+	}
+	if uint64(len(p)) > x.remaining {
+		p = p[:x.remaining]
+	}
+	n := 0
+	for len(p) > 0 {
+		if x.offset == Size {
+			x.nextBlock()
+		}
+		c := copy(p, x.block[x.offset:])
+		x.offset += c
+		x.remaining -= uint64(c)
+		n += c
+		p = p[c:]
+	}
+	return n, nil
+}
+
+// Remaining returns the number of output bytes left before Read returns
+// io.EOF, or -1 for an output stream of unknown length.
+func (x *XOF) Remaining() int64 {
+	if x.length == OutputLengthUnknown {
+		return -1
+	}
+	return int64(x.remaining)
+}
+
+// nextBlock hashes the root digest into the output block at nodeOffset.
+// The last block of a stream of known length is truncated to the bytes
+// that remain, which is recorded as its digest size.
+func (x *XOF) nextBlock() {
+	size := Size
+	if x.remaining < Size {
+		size = int(x.remaining)
+	}
+	d := digest{
+		size:       size,
+		leafSize:   Size,
+		nodeOffset: uint64(x.length)<<32 | uint64(x.nodeOffset),
+		innerSize:  Size,
+	}
+	d.Reset()
+	d.Write(x.h0[:])
+	x.block = d.checkSum()
+	x.offset = 0
+	x.nodeOffset++
+}
+
+// MarshalBinary encodes the state of x, including its key and output
+// position, so that an XOF restored by UnmarshalBinary continues the
+// exact same input or output stream. The encoding holds the key in
+// clear and must be stored accordingly.
+func (x *XOF) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, xofStateSize)
+	b = append(b, xofMagic...)
+	b = binary.LittleEndian.AppendUint32(b, x.length)
+	if x.reading {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	var key [KeySize]byte
+	b = append(b, byte(copy(key[:], x.d.key)))
+	b = append(b, key[:]...)
+	for _, h := range x.d.h {
+		b = binary.LittleEndian.AppendUint64(b, h)
+	}
+	b = binary.LittleEndian.AppendUint64(b, x.d.t[0])
+	b = binary.LittleEndian.AppendUint64(b, x.d.t[1])
+	b = binary.LittleEndian.AppendUint16(b, uint16(x.d.buflen))
+	b = append(b, x.d.buf[:]...)
+	b = append(b, x.h0[:]...)
+	b = append(b, x.block[:]...)
+	b = append(b, byte(x.offset))
+	b = binary.LittleEndian.AppendUint32(b, x.nodeOffset)
+	b = binary.LittleEndian.AppendUint64(b, x.remaining)
+	return b, nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary.
+func (x *XOF) UnmarshalBinary(b []byte) error {
+	if len(b) != xofStateSize || string(b[:len(xofMagic)]) != xofMagic {
+		return errXOFState
+	}
+	b = b[len(xofMagic):]
+	length := binary.LittleEndian.Uint32(b)
+	reading, keylen := b[4], int(b[5])
+	b = b[6:]
+	if reading > 1 || keylen > KeySize {
+		return errXOFState
+	}
+	d := newDigest()
+	d.key = append([]byte(nil), b[:keylen]...)
+	d.nodeOffset = uint64(length) << 32
+	b = b[KeySize:]
+	for i := range d.h {
+		d.h[i] = binary.LittleEndian.Uint64(b[i*8:])
+	}
+	b = b[8*8:]
+	d.t[0] = binary.LittleEndian.Uint64(b)
+	d.t[1] = binary.LittleEndian.Uint64(b[8:])
+	d.buflen = int(binary.LittleEndian.Uint16(b[16:]))
+	b = b[18:]
+	copy(d.buf[:], b)
+	b = b[2*BlockSize:]
+
+	next := XOF{d: *d, length: length, reading: reading == 1}
+	copy(next.h0[:], b)
+	copy(next.block[:], b[Size:])
+	b = b[2*Size:]
+	next.offset = int(b[0])
+	next.nodeOffset = binary.LittleEndian.Uint32(b[1:])
+	next.remaining = binary.LittleEndian.Uint64(b[5:])
+	if d.buflen > len(d.buf) || next.offset > Size {
+		return errXOFState
+	}
+	*x = next
+	return nil
+}
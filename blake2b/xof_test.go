@@ -0,0 +1,253 @@
+package blake2b
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestXOF(t *testing.T) {
+	input := make([]byte, 256)
+	for i := range input {
+		input[i] = byte(i)
+	}
+	for i, v := range []struct {
+		input    []byte
+		length   uint32
+		expected string
+	}{
+		{nil, 64, "C5EF3D8845B9B2BA8EA28E9326C9E46E7A5843AD42BACAF927798BEAF554A43CA0830CCF8BB4A24CE1B1D82BD2DA971AFB2BE73919CC5FFF8E7C6A20F87284FA"},
+		{[]byte("abc"), 1, "CD"},
+		{[]byte("abc"), 100, "E0F82B71C07860B65BE612D2633BECC46596A6C12A8772B561ADEC35721B7A5C44A7E075E8A3BC8C4FC8390A197BE2085B4AA4385C207F24E46415DEFC659AFD73BACB288080B10849AEEA386C60CD3FA04C9BCBFEEBAED6E98634D696B9D5BDEF0AD2C5"},
+		{input, 64*3 + 5, "EA65942FF43FA6092E4056100586228F2D44CD8F7020D7C9A0927AF28FC4CFDA7D7F8202B1DEC3AC153D186B97729508F8875BC46C5213BB3254717FACF81FB1B750F56B0E25923D428AEE8F06FFA9F55BB9D06B7144C98926F9DC82CB7DE678D0D217816D73821B34E60EC41A64E4B9CBABFA8A88BA9559DED2AD1C2E5C3B54654AF840715D7DE483C1844ED17E8D515D13016AD5DBB83E09D1EAB459B68720672FFE1D8AC982FB5FFEBAF08B7B94FCDD9481CE3BC07DF4D4AACDF06B4F145871133B8296"},
+	} {
+		x := NewXOF(v.length)
+		x.Write(v.input)
+		out, err := io.ReadAll(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := fmt.Sprintf("%X", out); actual != v.expected {
+			t.Errorf("bad output (%d): expected=%s, actual=%s", i, v.expected, actual)
+		}
+	}
+}
+
+func TestXOFUnknownLength(t *testing.T) {
+	x := NewXOF(OutputLengthUnknown)
+	x.Write([]byte("abc"))
+	out := make([]byte, 200)
+	if _, err := io.ReadFull(x, out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "AE080C1EFBCF7F60ED52A04161D02B7EE63BED362534F0661DA02C6E40CD208946D066B86B3DFF620E57ACEA9CD72D3056CF6CB0C18341452A17CE2CCED67B702669BF0BED358C1B708E97DE2533B294CDD5E9E229678BE36399B5B28D6541C4BC4E3079FB8A0FBDF6023A65F36C654947CE7C114A243670DAD347F03275B5C5BD383E8D53FD0FE8F387EA3D6445FC6510C8A3B9FC5CCED503B824504F0471BD3AC19514BDAF7A3C021DC44CA8FF6D656A6007D43B552F07560E8B79217060C1387971E8E3EE97D9"
+	if actual := fmt.Sprintf("%X", out); actual != expected {
+		t.Errorf("bad output: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+func TestXOFChunkedRead(t *testing.T) {
+	x := NewXOF(1000)
+	x.Write([]byte("abc"))
+	expected, _ := io.ReadAll(x)
+
+	x.Reset()
+	x.Write([]byte("abc"))
+	var actual []byte
+	buf := make([]byte, 7)
+	for {
+		n, err := x.Read(buf)
+		actual = append(actual, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("chunked read differs: expected=%X, actual=%X", expected, actual)
+	}
+}
+
+func TestXOFWriteAfterRead(t *testing.T) {
+	defer func() {
+		if recover() != errWriteAfterRead {
+			t.Error("expected panic for write after read")
+		}
+	}()
+	x := NewXOF(32)
+	x.Read(make([]byte, 1))
+	x.Write([]byte("abc"))
+}
+
+func TestKeyedXOF(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for i, v := range []struct {
+		key      []byte
+		input    []byte
+		length   uint32
+		expected string
+	}{
+		{key, []byte("abc"), 100, "D3DFCE73A21A9A51524BE7AA495F13FB37716CE9DDCA2521E2244A5DE68E32C933AE9BD1D4F65F119D411FADD99B81F6ACDA7F2AAF691A3B9867BF0763CD98404C03C88FFF787BB848321DBCEE3D2E86B49C9E88C70F68957183C44AFA45216FECC919A5"},
+		{[]byte("key"), nil, 32, "8434193ED25726F8CBE36E6960E7FBA841CCF03C245675549E2ABB227BBAF65C"},
+		{nil, []byte("abc"), 100, "E0F82B71C07860B65BE612D2633BECC46596A6C12A8772B561ADEC35721B7A5C44A7E075E8A3BC8C4FC8390A197BE2085B4AA4385C207F24E46415DEFC659AFD73BACB288080B10849AEEA386C60CD3FA04C9BCBFEEBAED6E98634D696B9D5BDEF0AD2C5"},
+	} {
+		x := NewKeyedXOF(v.length, v.key)
+		x.Write(v.input)
+		out, err := io.ReadAll(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := fmt.Sprintf("%X", out); actual != v.expected {
+			t.Errorf("bad output (%d): expected=%s, actual=%s", i, v.expected, actual)
+		}
+	}
+}
+
+func TestKeyedXOFKeyChange(t *testing.T) {
+	stream := func(key string) []byte {
+		x := NewKeyedXOF(OutputLengthUnknown, []byte(key))
+		x.Write([]byte("abc"))
+		out := make([]byte, 16*Size)
+		io.ReadFull(x, out)
+		return out
+	}
+	a, b := stream("key one"), stream("key two")
+	for i := 0; i < len(a); i += Size {
+		if bytes.Equal(a[i:i+Size], b[i:i+Size]) {
+			t.Errorf("output block %d does not depend on the key", i/Size)
+		}
+	}
+}
+
+func TestKeyedXOFInvalidKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for oversized key")
+		}
+	}()
+	NewKeyedXOF(32, make([]byte, KeySize+1))
+}
+
+func TestXOFMarshal(t *testing.T) {
+	for _, length := range []uint32{1000, OutputLengthUnknown} {
+		x := NewKeyedXOF(length, []byte("key"))
+		x.Write([]byte("some input "))
+
+		// Marshal while absorbing input.
+		state, err := x.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var y XOF
+		if err := y.UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+		x.Write([]byte("and more"))
+		y.Write([]byte("and more"))
+
+		// Marshal in the middle of an output block.
+		head := make([]byte, 100)
+		x.Read(head)
+		y.Read(head)
+		if state, err = x.MarshalBinary(); err != nil {
+			t.Fatal(err)
+		}
+		var z XOF
+		if err := z.UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := make([]byte, 500)
+		io.ReadFull(x, expected)
+		for _, r := range []*XOF{&y, &z} {
+			actual := make([]byte, 500)
+			io.ReadFull(r, actual)
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("bad continuation (length %d): expected=%X, actual=%X", length, expected, actual)
+			}
+		}
+
+		// Reset restores the key.
+		z.Reset()
+		z.Write([]byte("x"))
+		ref := NewKeyedXOF(length, []byte("key"))
+		ref.Write([]byte("x"))
+		a, b := make([]byte, 64), make([]byte, 64)
+		z.Read(a)
+		ref.Read(b)
+		if !bytes.Equal(a, b) {
+			t.Errorf("bad output after Reset: expected=%X, actual=%X", b, a)
+		}
+	}
+}
+
+func TestXOFUnmarshalInvalid(t *testing.T) {
+	state, _ := NewXOF(32).MarshalBinary()
+	for _, b := range [][]byte{nil, state[:len(state)-1], append([]byte("xxxx"), state[4:]...)} {
+		var x XOF
+		if err := x.UnmarshalBinary(b); err == nil {
+			t.Errorf("expected error for state of %d bytes", len(b))
+		}
+	}
+}
+
+func TestXOFSum(t *testing.T) {
+	input := []byte("key derivation input")
+	for _, key := range [][]byte{nil, []byte("xof key")} {
+		for _, length := range []int{1, 31, Size, Size + 1, 200, 1000} {
+			x := NewKeyedXOF(uint32(length), key)
+			x.Write(input)
+			expected, err := io.ReadAll(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := make([]byte, length)
+			XOFSum(input, key, out)
+			if !bytes.Equal(out, expected) {
+				t.Errorf("bad output (%d, key %q): expected=%X, actual=%X", length, key, expected, out)
+			}
+		}
+	}
+}
+
+func TestXOFSumEmpty(t *testing.T) {
+	XOFSum([]byte("data"), nil, nil)
+	XOFSum([]byte("data"), []byte("xof key"), []byte{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for oversized key")
+		}
+	}()
+	XOFSum([]byte("data"), make([]byte, KeySize+1), make([]byte, 8))
+}
+
+func TestXOFRemaining(t *testing.T) {
+	x := NewXOF(150)
+	x.Write([]byte("input"))
+	if r := x.Remaining(); r != 150 {
+		t.Errorf("bad remaining before Read: %d", r)
+	}
+	buf := make([]byte, 40)
+	for _, expected := range []int64{110, 70, 30, 0} {
+		x.Read(buf)
+		if r := x.Remaining(); r != expected {
+			t.Errorf("bad remaining: expected=%d, actual=%d", expected, r)
+		}
+	}
+	if n, err := x.Read(buf); n != 0 || err != io.EOF {
+		t.Errorf("Read at zero remaining: n=%d, err=%v", n, err)
+	}
+
+	x.Reset()
+	if r := x.Remaining(); r != 150 {
+		t.Errorf("bad remaining after Reset: %d", r)
+	}
+	if r := NewXOF(OutputLengthUnknown).Remaining(); r != -1 {
+		t.Errorf("bad remaining for unknown length: %d", r)
+	}
+}
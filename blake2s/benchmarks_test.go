@@ -0,0 +1,22 @@
+package blake2s
+
+import (
+	"hash"
+	"testing"
+)
+
+func benchmarkHash(b *testing.B, hash func() hash.Hash) {
+	b.SetBytes(1024 * 1024)
+	data := make([]byte, 1024)
+	for i := 0; i < b.N; i++ {
+		h := hash()
+		for j := 0; j < 1024; j++ {
+			h.Write(data)
+		}
+		h.Sum(nil)
+	}
+}
+
+func BenchmarkBlake2s(b *testing.B) {
+	benchmarkHash(b, New)
+}
@@ -1,11 +1,17 @@
 // Package blake2s implements the BLAKE2s hash algorithm.
 //
+// The package has no lazily initialized state: its tables are fixed and
+// its self-test runs in init, so digests may be created and used from
+// any number of goroutines at once. A single digest is not safe for
+// concurrent use.
+//
 // Written by Devi Mandiri <devi.mandiri@gmail.com>
 package blake2s
 
 import (
 	"encoding/binary"
 	"hash"
+	"io"
 )
 
 // The Blake2s blocksize in bytes.
@@ -14,6 +20,12 @@ const BlockSize  = 64
 // The Blake2s maximum key size.
 const KeySize = 32
 
+// The Blake2s digest size in bytes.
+const Size = 32
+
+// The size of the buffer used by ReadFrom.
+const readBufSize = 32 * 1024
+
 var (
 	// The Blake2s IV.
 	iv = [8]uint32{
@@ -39,23 +51,41 @@ var (
 type digest struct {
 	h        [8]uint32
 	t        [2]uint32
-	f        [2]uint32
-	buf      [2*BlockSize]byte
+	buf      [bufSize]byte
 	buflen   int
 	key      []byte
+	size     int
+
+	// Tree hashing parameters, see parallel.go.
+	fanout     uint8
+	depth      uint8
+	nodeOffset uint64
+	nodeDepth  uint8
+	innerSize  uint8
+	lastNode   bool
+	noKeyBlock bool
+
+	// Scratch buffer for ReadFrom, allocated on first use.
+	rbuf []byte
+}
+
+// newDigest returns an unkeyed digest configured for sequential mode.
+func newDigest() *digest {
+	return &digest{size: Size, fanout: 1, depth: 1}
 }
 
 // New returns a new hash.Hash computing the Blake2s checksum.
 func New() hash.Hash {
-	d := new(digest)
+	d := newDigest()
 	d.Reset()
 	return d
 }
 
 // NewKeyed returns a new hash.Hash computing the Blake2s checksum
-// with the given key.
+// with the given key. A key of up to KeySize bytes is used in full;
+// longer keys are truncated to their first KeySize bytes.
 func NewKeyed(key []byte) hash.Hash {
-	d := new(digest)
+	d := newDigest()
 	d.key = key
 	d.Reset()
 	return d
@@ -67,52 +97,77 @@ func (d *digest) Reset() {
 		keylen = KeySize
 	}
 	p := make([]byte, BlockSize)
-	p[0] = 32
+	p[0] = uint8(d.size)
 	p[1] = uint8(keylen)
-	p[2] = 1
-	p[3] = 1
+	p[2] = d.fanout
+	p[3] = d.depth
+	binary.LittleEndian.PutUint32(p[8:], uint32(d.nodeOffset))
+	binary.LittleEndian.PutUint16(p[12:], uint16(d.nodeOffset>>32))
+	p[14] = d.nodeDepth
+	p[15] = d.innerSize
 
-	d.f[0] = 0
-	d.f[1] = 0
 	d.t[0] = 0
 	d.t[1] = 0
 	d.buflen = 0
 	for i := 0; i < 8; i++ {
 		d.h[i] = iv[i] ^ binary.LittleEndian.Uint32(p[i*4:])
 	}
-	if keylen > 0 {
+	if keylen > 0 && !d.noKeyBlock {
 		block := make([]byte, BlockSize)
 		copy(block[:], d.key[:keylen])
-		d.Write(block)
+		d.absorb(block)
 	}
 }
 
+// Sigma returns the message word permutations of the 10 Blake2s rounds.
+// The table is returned by value for cross-validation and reduced-round
+// experiments; changing it does not affect hashing.
+func Sigma() [10][16]uint8 {
+	return sigma
+}
+
 func (*digest) BlockSize() int {
 	return 64
 }
 
+// MaxKeySize returns the maximal key length in bytes, KeySize.
+func (*digest) MaxKeySize() int {
+	return KeySize
+}
+
 func (d *digest) Size() int {
-	return 32
+	return d.size
 }
 
-// compress contains main algorithm of the Blake2s as defined in
+// compressGeneric contains main algorithm of the Blake2s as defined in
 // https://blake2.net/blake2_20130129.pdf
-func (d *digest) compress() {
+//
+// It compresses the first BlockSize bytes of block into h, with t the
+// byte counter including this block. last flags the final block of the
+// input, and lastNode the final block of the last node of a tree level.
+// compress dispatches to it, or to an assembly version where available.
+func compressGeneric(h *[8]uint32, block []byte, t [2]uint32, last, lastNode bool) {
 	var m, v [16]uint32
 	for i := 0; i < 16; i++ {
-		m[i] = binary.LittleEndian.Uint32(d.buf[i*4:])
+		m[i] = binary.LittleEndian.Uint32(block[i*4:])
 	}
 	for i := 0; i < 8; i++ {
-		v[i] = d.h[i]
+		v[i] = h[i]
 	}
 	v[8] = iv[0]
 	v[9] = iv[1]
 	v[10] = iv[2]
 	v[11] = iv[3]
-	v[12] = d.t[0] ^ iv[4]
-	v[13] = d.t[1] ^ iv[5]
-	v[14] = d.f[0] ^ iv[6]
-	v[15] = d.f[1] ^ iv[7]
+	v[12] = t[0] ^ iv[4]
+	v[13] = t[1] ^ iv[5]
+	v[14] = iv[6]
+	v[15] = iv[7]
+	if last {
+		v[14] = ^v[14]
+		if lastNode {
+			v[15] = ^v[15]
+		}
+	}
 
 	rotr32 := func (w uint32, c uint32) uint32 {
 		return (w>>c) | (w<<(32-c))
@@ -138,60 +193,90 @@ func (d *digest) compress() {
 		G(i, 7, 3, 4,  9, 14);
 	}
 	for i := 0; i < 8; i++ {
-		d.h[i] = d.h[i] ^ v[i] ^ v[i+8]
+		h[i] = h[i] ^ v[i] ^ v[i+8]
 	}
 }
 
 func (d *digest) incrementCounter(inc uint32) {
 	d.t[0] += inc
 	if d.t[0] < inc {
-		d.t[1]--
+		d.t[1]++
 	}
 }
 
 func (d *digest) Write(buf []byte) (int, error) {
-	inlen := len(buf)
-	offset := 0
-	for inlen > 0 {
-		left := d.buflen
-		fill := 2*BlockSize - left
-		if inlen > fill {
-			copy(d.buf[left:], buf[offset:fill])
-			d.buflen += fill
+	d.absorb(buf)
+	return len(buf), nil
+}
+
+// absorb adds buf to the staging buffer. The last block is only compressed
+// once more input arrives, since it may need to be flagged as final. With
+// the one-block buffer of the blake2s_lowmem build, a full buffer is
+// compressed as soon as the next byte arrives.
+func (d *digest) absorb(buf []byte) {
+	for len(buf) > 0 {
+		if d.buflen == len(d.buf) {
 			d.incrementCounter(BlockSize)
-			d.compress()
+			compress(&d.h, d.buf[:], d.t, false, false)
 			copy(d.buf[:BlockSize], d.buf[BlockSize:])
 			d.buflen -= BlockSize
-			offset += fill
-			inlen -= fill
-		} else {
-			copy(d.buf[left:], buf[offset:])
-			d.buflen += inlen
-			offset += inlen
-			inlen -= inlen
+		}
+		n := copy(d.buf[d.buflen:], buf)
+		d.buflen += n
+		buf = buf[n:]
+	}
+}
+
+// ReadFrom absorbs data from r until EOF. It returns the number of bytes
+// absorbed and any error encountered other than io.EOF.
+func (d *digest) ReadFrom(r io.Reader) (int64, error) {
+	if d.rbuf == nil {
+		d.rbuf = make([]byte, readBufSize)
+	}
+	var total int64
+	for {
+		n, err := r.Read(d.rbuf)
+		if n > 0 {
+			w, werr := d.Write(d.rbuf[:n])
+			total += int64(w)
+			if werr != nil {
+				return total, werr
+			}
+		}
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
 		}
 	}
-	return 0, nil;
 }
 
-// Sum returns the Blake2s checksum of the data.
+// Sum appends the Blake2s checksum of the data to buf. It does not
+// change the underlying hash state.
 func (d *digest) Sum(buf []byte) []byte {
+	dd := *d
+	sum := dd.checkSum()
+	return append(buf, sum[:d.size]...)
+}
+
+// checkSum finalizes the hash state and returns the full-length checksum.
+func (d *digest) checkSum() [Size]byte {
 	if d.buflen > BlockSize {
 		d.incrementCounter(BlockSize)
-		d.compress()
+		compress(&d.h, d.buf[:], d.t, false, false)
 		d.buflen -= BlockSize
 		copy(d.buf[:d.buflen], d.buf[BlockSize:])
 	}
 	d.incrementCounter(uint32(d.buflen))
-	d.f[0] = 0xffffffff;
-	j := 2*BlockSize - d.buflen
+	j := len(d.buf) - d.buflen
 	for i := 0; i < j; i++ {
 		d.buf[i+d.buflen] = 0
 	}
-	d.compress()
-	buffer := make([]byte, 32)
+	compress(&d.h, d.buf[:], d.t, true, d.lastNode)
+	var sum [Size]byte
 	for i := 0; i < 8; i++ {
-		binary.LittleEndian.PutUint32(buffer[i*4:], d.h[i])
+		binary.LittleEndian.PutUint32(sum[i*4:], d.h[i])
 	}
-	return append(buf, buffer[:]...)
+	return sum
 }
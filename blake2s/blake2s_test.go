@@ -0,0 +1,238 @@
+package blake2s
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestWriteLength(t *testing.T) {
+	input := make([]byte, 1000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	expected := "1C067A5E746FB0F6734EFAC9A8CDB0E11061F0077F255184365C690115392501"
+
+	for _, chunk := range []int{1, 7, 63, 64, 65, 128, 300, 1000} {
+		h := New()
+		for i := 0; i < len(input); i += chunk {
+			end := i + chunk
+			if end > len(input) {
+				end = len(input)
+			}
+			if n, err := h.Write(input[i:end]); n != end-i || err != nil {
+				t.Fatalf("Write(%d): n=%d, err=%v", end-i, n, err)
+			}
+		}
+		actual := fmt.Sprintf("%X", h.Sum(nil))
+		if actual != expected {
+			t.Errorf("bad hash (chunk %d): expected=%s, actual=%s", chunk, expected, actual)
+		}
+	}
+}
+
+func TestSumIdempotent(t *testing.T) {
+	h := New()
+	h.Write([]byte("one two"))
+	first := h.Sum(nil)
+	if second := h.Sum(nil); !bytes.Equal(first, second) {
+		t.Errorf("Sum changed the hash state: first=%X, second=%X", first, second)
+	}
+
+	h.Write([]byte(" three"))
+	ref := New()
+	ref.Write([]byte("one two three"))
+	if actual, expected := h.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash after Sum: expected=%X, actual=%X", expected, actual)
+	}
+}
+
+func TestKeySizeBoundary(t *testing.T) {
+	key := make([]byte, KeySize+1)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	input := []byte{0, 1, 2}
+	expected := "1D220DBE2EE134661FDF6D9E74B41704710556F2F6E5A091B227697445DBEA6B"
+
+	h := NewKeyed(key[:KeySize])
+	h.Write(input)
+	if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != expected {
+		t.Errorf("bad hash with KeySize key: expected=%s, actual=%s", expected, actual)
+	}
+
+	h = NewKeyed(key)
+	h.Write(input)
+	if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != expected {
+		t.Errorf("oversized key was not truncated: expected=%s, actual=%s", expected, actual)
+	}
+
+	h = NewKeyed(key[:KeySize-1])
+	h.Write(input)
+	if actual := fmt.Sprintf("%X", h.Sum(nil)); actual == expected {
+		t.Error("key of KeySize-1 bytes hashed like a full key")
+	}
+}
+
+var vectors2s = []struct {
+	length int
+	output string
+}{
+	{0, "69217A3079908094E11121D042354A7C1F55B6482CA1A51E1B250DFD1ED0EEF9"},
+	{1, "E34D74DBAF4FF4C6ABD871CC220451D2EA2648846C7757FBAAC82FE51AD64BEA"},
+	{63, "E57CB79487DD57902432B250733813BD96A84EFCE59F650FAC26E6696AEFAFC3"},
+	{64, "56F34E8B96557E90C1F24B52D0C89D51086ACF1B00F634CF1DDE9233B8EAAA3E"},
+	{65, "1B53EE94AAF34E4B159D48DE352C7F0661D0A40EDFF95A0B1639B4090E974472"},
+	{127, "F18417B39D617AB1C18FDF91EBD0FC6D5516BB34CF39364037BCE81FA04CECB1"},
+	{128, "1FA877DE67259D19863A2A34BCC6962A2B25FCBF5CBECD7EDE8F1FA36688A796"},
+	{129, "5BD169E67C82C2C2E98EF7008BDF261F2DDF30B1C00F9E7F275BB3E8A28DC9A2"},
+	{1000, "1C067A5E746FB0F6734EFAC9A8CDB0E11061F0077F255184365C690115392501"},
+}
+
+func TestBlake2s(t *testing.T) {
+	for _, v := range vectors2s {
+		input := make([]byte, v.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+
+		h := New()
+		h.Write(input)
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.output {
+			t.Errorf("bad hash (%d): expected=%s, actual=%s", v.length, v.output, actual)
+		}
+
+		h.Reset()
+		for i := range input {
+			h.Write(input[i : i+1])
+		}
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.output {
+			t.Errorf("bad streaming hash (%d): expected=%s, actual=%s", v.length, v.output, actual)
+		}
+	}
+}
+
+func TestEmptyWrite(t *testing.T) {
+	input := make([]byte, 1000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	expected := "1C067A5E746FB0F6734EFAC9A8CDB0E11061F0077F255184365C690115392501"
+
+	h := New()
+	d := h.(*digest)
+	start := 0
+	for _, end := range []int{0, 1, BlockSize, 2 * BlockSize, 2*BlockSize + 1, 1000} {
+		h.Write(input[start:end])
+		start = end
+		for _, empty := range [][]byte{nil, {}} {
+			before := *d
+			if n, err := h.Write(empty); n != 0 || err != nil {
+				t.Fatalf("Write(%v): n=%d, err=%v", empty, n, err)
+			}
+			if d.h != before.h || d.t != before.t || d.buflen != before.buflen {
+				t.Errorf("empty write after %d bytes changed the state", end)
+			}
+		}
+	}
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+	if actual != expected {
+		t.Errorf("bad hash: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+// The permutations from section 2.7 of RFC 7693.
+var specSigma = [10][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+func TestSigma(t *testing.T) {
+	table := Sigma()
+	for r, row := range table {
+		if row != specSigma[r%10] {
+			t.Errorf("bad permutation (round %d): expected=%v, actual=%v", r, specSigma[r%10], row)
+		}
+	}
+	table[0][0] = 1
+	if Sigma()[0][0] != 0 {
+		t.Error("modifying the returned table changed sigma")
+	}
+}
+
+func TestCounterCarry(t *testing.T) {
+	d := New().(*digest)
+	d.t[0] = 1<<32 - 64
+	d.incrementCounter(BlockSize)
+	if d.t[0] != 0 || d.t[1] != 1 {
+		t.Errorf("bad counter after wrap: t=%v", d.t)
+	}
+	d.incrementCounter(BlockSize)
+	if d.t[0] != 64 || d.t[1] != 1 {
+		t.Errorf("bad counter after wrap: t=%v", d.t)
+	}
+
+	d = New().(*digest)
+	d.t[0] = 1<<32 - 1
+	d.incrementCounter(0)
+	if d.t[0] != 1<<32-1 || d.t[1] != 0 {
+		t.Errorf("bad counter without carry: t=%v", d.t)
+	}
+}
+
+// TestCounterCarryHash presets the counter 64 bytes short of 4 GiB, so
+// the 300 bytes of input cross the boundary where t[0] carries into t[1].
+// The expected value comes from a reference model with the same preset.
+func TestCounterCarryHash(t *testing.T) {
+	input := make([]byte, 300)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+
+	h := New()
+	h.(*digest).t[0] = 1<<32 - 64
+	h.Write(input)
+	actual := fmt.Sprintf("%X", h.Sum(nil))
+
+	expected := "54EA40130166D9837A523DD67E3D3DC391514D71FB5C54F8FF75DDCCCB30FD5A"
+	if actual != expected {
+		t.Errorf("bad hash across counter wrap: expected=%s, actual=%s", expected, actual)
+	}
+}
+
+// TestChunkedWrites feeds the vectors in chunks around the block size,
+// which move data through the staging buffer differently in the default
+// and the blake2s_lowmem builds; run the tests with both.
+func TestChunkedWrites(t *testing.T) {
+	if len(newDigest().buf) != bufSize {
+		t.Fatalf("bad staging buffer size: %d", len(newDigest().buf))
+	}
+	for _, v := range vectors2s {
+		input := make([]byte, v.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+		for _, chunk := range []int{1, 63, BlockSize, 65, 2 * BlockSize} {
+			h := New()
+			for rest := input; len(rest) > 0; {
+				n := chunk
+				if n > len(rest) {
+					n = len(rest)
+				}
+				h.Write(rest[:n])
+				rest = rest[n:]
+			}
+			if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.output {
+				t.Errorf("bad hash (%d, chunks of %d): expected=%s, actual=%s", v.length, chunk, v.output, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !blake2s_lowmem
+
+package blake2s
+
+// bufSize is the size of the staging buffer of a digest. Two blocks let
+// absorb move whole blocks with few copies; build with the
+// blake2s_lowmem tag to use a single block on memory-constrained targets.
+const bufSize = 2 * BlockSize
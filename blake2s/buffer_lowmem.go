@@ -0,0 +1,9 @@
+//go:build blake2s_lowmem
+
+package blake2s
+
+// bufSize is the size of the staging buffer of a digest. The
+// blake2s_lowmem build holds a single block, saving 64 bytes per digest
+// and per copy made by Sum, at the cost of compressing from the buffer
+// one block at a time.
+const bufSize = BlockSize
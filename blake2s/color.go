@@ -0,0 +1,15 @@
+package blake2s
+
+// ColorSeed derives an RGB color from name, for identicons and avatars.
+// The three components are the 3-byte Blake2s checksum of name, so the
+// same name always gets the same color and different names spread evenly
+// over the 2^24 colors. With a digest size of 3 in the parameter block,
+// the color is not a prefix of the full checksum of name.
+func ColorSeed(name []byte) (r, g, b uint8) {
+	d := newDigest()
+	d.size = 3
+	d.Reset()
+	d.Write(name)
+	sum := d.checkSum()
+	return sum[0], sum[1], sum[2]
+}
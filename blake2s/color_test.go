@@ -0,0 +1,28 @@
+package blake2s
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestColorSeed(t *testing.T) {
+	r, g, b := ColorSeed([]byte("alice"))
+	if actual, expected := fmt.Sprintf("%02x%02x%02x", r, g, b), "e79fc9"; actual != expected {
+		t.Errorf("bad color: expected=%s, actual=%s", expected, actual)
+	}
+	if r2, g2, b2 := ColorSeed([]byte("alice")); r2 != r || g2 != g || b2 != b {
+		t.Error("color is not deterministic")
+	}
+}
+
+func TestColorSeedSpread(t *testing.T) {
+	colors := map[[3]uint8]bool{}
+	for i := 0; i < 1000; i++ {
+		r, g, b := ColorSeed([]byte(fmt.Sprintf("user%d", i)))
+		colors[[3]uint8{r, g, b}] = true
+	}
+	// 1000 names over 2^24 colors collide with a probability of about 3%.
+	if len(colors) < 998 {
+		t.Errorf("too many color collisions: %d distinct colors for 1000 names", len(colors))
+	}
+}
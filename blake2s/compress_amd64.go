@@ -0,0 +1,30 @@
+//go:build amd64 && !purego
+
+package blake2s
+
+// useSSE2 selects the SSE2 compression function. SSE2 is part of the
+// amd64 baseline, so it is always available; tests clear it to exercise
+// the generic code.
+var useSSE2 = true
+
+// compressSSE2 is the SSE2 version of compressGeneric. c holds the
+// counter and the finalization flags, the last row of the initial state
+// before it is mixed with the IV. block must be at least BlockSize bytes.
+//
+//go:noescape
+func compressSSE2(h *[8]uint32, c *[4]uint32, block []byte)
+
+func compress(h *[8]uint32, block []byte, t [2]uint32, last, lastNode bool) {
+	if !useSSE2 {
+		compressGeneric(h, block, t, last, lastNode)
+		return
+	}
+	c := [4]uint32{t[0], t[1]}
+	if last {
+		c[2] = ^uint32(0)
+		if lastNode {
+			c[3] = ^uint32(0)
+		}
+	}
+	compressSSE2(h, &c, block[:BlockSize])
+}
@@ -0,0 +1,95 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+DATA iv0<>+0x00(SB)/4, $0x6a09e667
+DATA iv0<>+0x04(SB)/4, $0xbb67ae85
+DATA iv0<>+0x08(SB)/4, $0x3c6ef372
+DATA iv0<>+0x0c(SB)/4, $0xa54ff53a
+GLOBL iv0<>(SB), (NOPTR+RODATA), $16
+
+DATA iv1<>+0x00(SB)/4, $0x510e527f
+DATA iv1<>+0x04(SB)/4, $0x9b05688c
+DATA iv1<>+0x08(SB)/4, $0x1f83d9ab
+DATA iv1<>+0x0c(SB)/4, $0x5be0cd19
+GLOBL iv1<>(SB), (NOPTR+RODATA), $16
+
+// ROTR rotates the 32-bit lanes of x right by n bits, using t as scratch.
+#define ROTR(n, x, t) \
+	MOVO  x, t;        \
+	PSRLL $n, x;       \
+	PSLLL $(32-n), t;  \
+	PXOR  t, x
+
+// HALF is one half of the G function, applied to the four columns or
+// diagonals held in the rows a, b, c and d, with message words m.
+#define HALF(a, b, c, d, m, r1, r2, t) \
+	PADDL m, a;       \
+	PADDL b, a;       \
+	PXOR  a, d;       \
+	ROTR(r1, d, t);   \
+	PADDL d, c;       \
+	PXOR  c, b;       \
+	ROTR(r2, b, t)
+
+// LOAD stores message word i to offset off of the stack scratch space.
+#define LOAD(i, off) \
+	MOVL (i*4)(SI), R8; \
+	MOVL R8, off(SP)
+
+// ROUND runs one round over the rows X0 to X3 with the message words
+// permuted by the given sigma row. The words are gathered on the stack
+// into X4 to X7, the inputs of the four halves.
+#define ROUND(s0, s1, s2, s3, s4, s5, s6, s7, s8, s9, s10, s11, s12, s13, s14, s15) \
+	LOAD(s0, 0); LOAD(s2, 4); LOAD(s4, 8); LOAD(s6, 12);       \
+	LOAD(s1, 16); LOAD(s3, 20); LOAD(s5, 24); LOAD(s7, 28);    \
+	LOAD(s8, 32); LOAD(s10, 36); LOAD(s12, 40); LOAD(s14, 44); \
+	LOAD(s9, 48); LOAD(s11, 52); LOAD(s13, 56); LOAD(s15, 60); \
+	MOVOU 0(SP), X4;                                           \
+	MOVOU 16(SP), X5;                                          \
+	MOVOU 32(SP), X6;                                          \
+	MOVOU 48(SP), X7;                                          \
+	HALF(X0, X1, X2, X3, X4, 16, 12, X8);                      \
+	HALF(X0, X1, X2, X3, X5, 8, 7, X8);                        \
+	PSHUFD $0x39, X1, X1;                                      \
+	PSHUFD $0x4e, X2, X2;                                      \
+	PSHUFD $0x93, X3, X3;                                      \
+	HALF(X0, X1, X2, X3, X6, 16, 12, X8);                      \
+	HALF(X0, X1, X2, X3, X7, 8, 7, X8);                        \
+	PSHUFD $0x93, X1, X1;                                      \
+	PSHUFD $0x4e, X2, X2;                                      \
+	PSHUFD $0x39, X3, X3
+
+// func compressSSE2(h *[8]uint32, c *[4]uint32, block []byte)
+TEXT ·compressSSE2(SB), NOSPLIT, $64-40
+	MOVQ h+0(FP), AX
+	MOVQ c+8(FP), BX
+	MOVQ block_base+16(FP), SI
+
+	MOVOU 0(AX), X0
+	MOVOU 16(AX), X1
+	MOVO  X0, X10
+	MOVO  X1, X11
+	MOVOU iv0<>(SB), X2
+	MOVOU iv1<>(SB), X3
+	MOVOU 0(BX), X9
+	PXOR  X9, X3
+
+	ROUND(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15)
+	ROUND(14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3)
+	ROUND(11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4)
+	ROUND(7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8)
+	ROUND(9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13)
+	ROUND(2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9)
+	ROUND(12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11)
+	ROUND(13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10)
+	ROUND(6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5)
+	ROUND(10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0)
+
+	PXOR  X2, X0
+	PXOR  X3, X1
+	PXOR  X10, X0
+	PXOR  X11, X1
+	MOVOU X0, 0(AX)
+	MOVOU X1, 16(AX)
+	RET
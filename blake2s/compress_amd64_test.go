@@ -0,0 +1,62 @@
+//go:build amd64 && !purego
+
+package blake2s
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestCompressSSE2(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	block := make([]byte, BlockSize)
+	for i := 0; i < 1000; i++ {
+		var h [8]uint32
+		for j := range h {
+			h[j] = rng.Uint32()
+		}
+		rng.Read(block)
+		tc := [2]uint32{rng.Uint32(), rng.Uint32()}
+		last, lastNode := i%2 == 1, i%4 == 3
+
+		expected, actual := h, h
+		compressGeneric(&expected, block, tc, last, lastNode)
+		compress(&actual, block, tc, last, lastNode)
+		if actual != expected {
+			t.Fatalf("bad state (%d): expected=%08X, actual=%08X", i, expected, actual)
+		}
+	}
+}
+
+func TestVectorsSSE2(t *testing.T) {
+	defer func(saved bool) { useSSE2 = saved }(useSSE2)
+	for _, sse2 := range []bool{false, true} {
+		useSSE2 = sse2
+		for _, v := range vectors2s {
+			input := make([]byte, v.length)
+			for i := range input {
+				input[i] = byte(i % 251)
+			}
+			h := New()
+			h.Write(input)
+			if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.output {
+				t.Errorf("bad hash (sse2 %v, %d): expected=%s, actual=%s", sse2, v.length, v.output, actual)
+			}
+		}
+	}
+}
+
+func benchmarkCompress(b *testing.B, sse2 bool) {
+	defer func(saved bool) { useSSE2 = saved }(useSSE2)
+	useSSE2 = sse2
+	benchmarkHash(b, New)
+}
+
+func BenchmarkCompressGeneric(b *testing.B) {
+	benchmarkCompress(b, false)
+}
+
+func BenchmarkCompressSSE2(b *testing.B) {
+	benchmarkCompress(b, true)
+}
@@ -0,0 +1,7 @@
+//go:build !amd64 || purego
+
+package blake2s
+
+func compress(h *[8]uint32, block []byte, t [2]uint32, last, lastNode bool) {
+	compressGeneric(h, block, t, last, lastNode)
+}
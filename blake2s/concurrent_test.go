@@ -0,0 +1,29 @@
+package blake2s
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// Run with -race to check that first use from many goroutines is safe.
+func TestConcurrentUse(t *testing.T) {
+	input := []byte("one two three")
+	ref := New()
+	ref.Write(input)
+	expected := ref.Sum(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h := New()
+			h.Write(input)
+			if actual := h.Sum(nil); !bytes.Equal(actual, expected) {
+				t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+			}
+		}()
+	}
+	wg.Wait()
+}
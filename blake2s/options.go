@@ -0,0 +1,44 @@
+package blake2s
+
+import (
+	"errors"
+	"hash"
+)
+
+// An Option configures a digest returned by NewWith.
+type Option func(*digest) error
+
+// NewWith returns a new hash.Hash computing the Blake2s checksum,
+// configured by the given options.
+func NewWith(opts ...Option) (hash.Hash, error) {
+	d := newDigest()
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+	d.Reset()
+	return d, nil
+}
+
+// WithSize sets the digest size in bytes, between 1 and Size.
+func WithSize(size int) Option {
+	return func(d *digest) error {
+		if size < 1 || size > Size {
+			return errors.New("blake2s: invalid digest size")
+		}
+		d.size = size
+		return nil
+	}
+}
+
+// WithKey sets the key of the digest, at most KeySize bytes long.
+func WithKey(key []byte) Option {
+	return func(d *digest) error {
+		if len(key) > KeySize {
+			return errors.New("blake2s: invalid key size")
+		}
+		d.key = append([]byte(nil), key...)
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package blake2s
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWithOptions(t *testing.T) {
+	for _, v := range []struct {
+		opts     []Option
+		expected string
+	}{
+		{[]Option{WithSize(16)}, "AA4938119B1DC7B87CBAD0FFD200D0AE"},
+		{[]Option{WithSize(Size)}, "508C5E8C327C14E2E1A72BA34EEB452F37458B209ED63A294D999B4C86675982"},
+		{[]Option{WithSize(20), WithKey([]byte("key"))}, "4ED699F7E71CE74340BA202A9C37C4CF9772AA3A"},
+	} {
+		h, err := NewWith(v.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Write([]byte("abc"))
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.expected {
+			t.Errorf("bad hash: expected=%s, actual=%s", v.expected, actual)
+		}
+		if h.Size() != len(v.expected)/2 {
+			t.Errorf("bad size: %d", h.Size())
+		}
+	}
+}
+
+func TestWithOptionsErrors(t *testing.T) {
+	for _, opt := range []Option{
+		WithSize(0),
+		WithSize(Size + 1),
+		WithKey(make([]byte, KeySize+1)),
+	} {
+		if _, err := NewWith(opt); err == nil {
+			t.Error("expected error for invalid option")
+		}
+	}
+}
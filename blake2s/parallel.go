@@ -0,0 +1,152 @@
+package blake2s
+
+import (
+	"hash"
+	"sync"
+)
+
+// The number of leaves of Blake2sp.
+const parallelism = 8
+
+// Writes of at least this many bytes are absorbed by the leaves
+// concurrently, one goroutine per leaf.
+const parallelThreshold = 16 * 1024
+
+// pdigest computes the Blake2sp checksum: the input is split into blocks
+// dealt round-robin to the leaves, and the root hashes the leaf digests.
+type pdigest struct {
+	leaves [parallelism]*digest
+	root   *digest
+	buf    [parallelism * BlockSize]byte
+	buflen int
+}
+
+// NewP returns a new hash.Hash computing the Blake2sp checksum, the
+// 8-way parallel variant of Blake2s.
+func NewP() hash.Hash {
+	return NewPKeyed(nil)
+}
+
+// NewPKeyed returns a new hash.Hash computing the Blake2sp checksum with
+// the given key. Like NewKeyed, it truncates keys longer than KeySize.
+func NewPKeyed(key []byte) hash.Hash {
+	return NewPSized(Size, key)
+}
+
+// NewPSized returns a new hash.Hash computing the size byte Blake2sp
+// checksum with the given key, which may be nil. The size is recorded in
+// the parameter blocks of all nodes, so the digest is not a truncation of
+// the Size byte one; the leaves still pass full-length digests to the
+// root. It panics if size is not between 1 and Size.
+func NewPSized(size int, key []byte) hash.Hash {
+	if size < 1 || size > Size {
+		panic("blake2s: invalid digest size")
+	}
+	p := new(pdigest)
+	for i := range p.leaves {
+		p.leaves[i] = newNode(key, size, 0, uint64(i), i == parallelism-1)
+	}
+	// The root records the key length but does not absorb the key.
+	p.root = newNode(key, size, 1, 0, true)
+	p.Reset()
+	return p
+}
+
+// newNode returns a node of the Blake2sp tree.
+func newNode(key []byte, size int, depth uint8, offset uint64, last bool) *digest {
+	d := newDigest()
+	d.key = key
+	d.size = size
+	d.fanout = parallelism
+	d.depth = 2
+	d.nodeOffset = offset
+	d.nodeDepth = depth
+	d.innerSize = Size
+	d.lastNode = last
+	d.noKeyBlock = depth > 0
+	return d
+}
+
+func (p *pdigest) Reset() {
+	for _, leaf := range p.leaves {
+		leaf.Reset()
+	}
+	p.root.Reset()
+	p.buflen = 0
+}
+
+func (*pdigest) BlockSize() int {
+	return BlockSize
+}
+
+func (p *pdigest) Size() int {
+	return p.root.Size()
+}
+
+func (*pdigest) MaxKeySize() int {
+	return KeySize
+}
+
+func (p *pdigest) Write(buf []byte) (int, error) {
+	n := len(buf)
+	if p.buflen > 0 {
+		fill := copy(p.buf[p.buflen:], buf)
+		p.buflen += fill
+		buf = buf[fill:]
+		if p.buflen < len(p.buf) {
+			return n, nil
+		}
+		p.absorbStripes(p.buf[:])
+		p.buflen = 0
+	}
+	whole := len(buf) - len(buf)%len(p.buf)
+	p.absorbStripes(buf[:whole])
+	p.buflen = copy(p.buf[:], buf[whole:])
+	return n, nil
+}
+
+// absorbStripes deals data, a multiple of parallelism blocks long, to the
+// leaves. Each leaf only ever sees its own blocks in input order, so the
+// result does not depend on how the goroutines are scheduled.
+func (p *pdigest) absorbStripes(data []byte) {
+	absorb := func(i int) {
+		leaf := p.leaves[i]
+		for off := i * BlockSize; off < len(data); off += len(p.buf) {
+			leaf.Write(data[off : off+BlockSize])
+		}
+	}
+	if len(data) < parallelThreshold {
+		for i := range p.leaves {
+			absorb(i)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(parallelism)
+	for i := range p.leaves {
+		go func(i int) {
+			defer wg.Done()
+			absorb(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Sum appends the Blake2sp checksum of the data to buf. It does not
+// change the underlying hash state.
+func (p *pdigest) Sum(buf []byte) []byte {
+	root := *p.root
+	for i, leaf := range p.leaves {
+		l := *leaf
+		if start := i * BlockSize; p.buflen > start {
+			end := start + BlockSize
+			if end > p.buflen {
+				end = p.buflen
+			}
+			l.Write(p.buf[start:end])
+		}
+		sum := l.checkSum()
+		root.Write(sum[:])
+	}
+	return root.Sum(buf)
+}
@@ -0,0 +1,154 @@
+package blake2s
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"math/rand"
+	"testing"
+)
+
+var vectors2sp = []struct {
+	length int
+	keyed  bool
+	output string
+}{
+	{0, false, "DD0E891776933F43C7D032B08A917E25741F8AA9A12C12E1CAC8801500F2CA4F"},
+	{1, false, "A6B9EECC25227AD788C99D3F236DEBC8DA408849E9A5178978727A81457F7239"},
+	{63, false, "1024C940BE7341449B5010522B509F65BBDC1287B455C2BB7F72B2C92FD0D189"},
+	{64, false, "52603B6CBFAD4966CB044CB267568385CF35F21E6C45CF30AED19832CB51E9F5"},
+	{511, false, "8E1E8EE1FFA0A01028FFF3BFF0AE9DF2565A82E55A04E9541BB78B9C4778336F"},
+	{512, false, "8D9E357863298DD8364B7CAF4234317F8A49F180D788B7ABFFB521925F1E1FF1"},
+	{513, false, "8A4BC3330497E681F15DAF24FC496044A1C32BF0A837A210399E1AE4AF7E92BE"},
+	{1000, false, "611F1AF6610CDAF674EC2C9178F6376EBE234EF50998A3BE3F1FA698FB779274"},
+	{40000, false, "A9C0F8B75744E19743E42C64D5CD1ABDF1A9B75EF93C92CD51B8C4BD9AF7175F"},
+	{0, true, "715CB13895AEB678F6124160BFF21465B30F4F6874193FC851B4621043F09CC6"},
+	{1, true, "40578FFA52BF51AE1866F4284D3A157FC1BCD36AC13CBDCB0377E4D0CD0B6603"},
+	{513, true, "99850C7C4FD3E6755D92842656CBD8BE768E894146182CBD0CC1D739AEBBBF0B"},
+	{40000, true, "A20BA0FAD7D506EFEC9B7293494E477645CBCACFFF654C5BC0B8906F18F8401D"},
+}
+
+// vectors2sp128 were computed with a reference model of Blake2sp whose
+// nodes all record a digest size of 16 and pass full-length leaf digests
+// to the root; the model reproduces the Size byte Blake2sp vectors.
+var vectors2sp128 = []struct {
+	length int
+	keyed  bool
+	output string
+}{
+	{0, false, "35F83A47D3B3F7632CE94D03154746A0"},
+	{1, false, "A61A33AAAEF00424A4D23DCFDE48A714"},
+	{64, false, "BE886A75ED56973300A016BA530430D5"},
+	{513, false, "9C91CEAB70E2CA6E37F2605CD74E1EBC"},
+	{1000, false, "DDE29EACEC114A172144B0B7AA7E7035"},
+	{513, true, "373A2110046E6C1B46EC125613018503"},
+}
+
+func TestBlake2sp(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for _, v := range vectors2sp {
+		input := make([]byte, v.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+
+		h := NewP()
+		if v.keyed {
+			h = NewPKeyed(key)
+		}
+		h.Write(input)
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.output {
+			t.Errorf("bad hash (%d, keyed %v): expected=%s, actual=%s", v.length, v.keyed, v.output, actual)
+		}
+	}
+}
+
+// referenceP computes the Blake2sp checksum sequentially.
+func referenceP(input []byte) []byte {
+	var leaves [parallelism]hash.Hash
+	for i := range leaves {
+		leaf := newNode(nil, Size, 0, uint64(i), i == parallelism-1)
+		leaf.Reset()
+		leaves[i] = leaf
+	}
+	for i := 0; i < len(input); i += BlockSize {
+		end := i + BlockSize
+		if end > len(input) {
+			end = len(input)
+		}
+		leaves[i/BlockSize%parallelism].Write(input[i:end])
+	}
+	root := newNode(nil, Size, 1, 0, true)
+	root.Reset()
+	for _, leaf := range leaves {
+		root.Write(leaf.Sum(nil))
+	}
+	return root.Sum(nil)
+}
+
+// Run with -race to check the concurrent leaf scheduling.
+func TestBlake2spRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		input := make([]byte, rng.Intn(4*parallelThreshold))
+		rng.Read(input)
+
+		h := NewP()
+		for rest := input; len(rest) > 0; {
+			n := rng.Intn(len(rest) + 1)
+			h.Write(rest[:n])
+			rest = rest[n:]
+		}
+		if actual, expected := h.Sum(nil), referenceP(input); !bytes.Equal(actual, expected) {
+			t.Fatalf("bad hash (%d): expected=%X, actual=%X", len(input), expected, actual)
+		}
+	}
+}
+
+func TestBlake2sp128(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for _, v := range vectors2sp128 {
+		input := make([]byte, v.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+
+		h := NewPSized(16, nil)
+		if v.keyed {
+			h = NewPSized(16, key)
+		}
+		h.Write(input)
+		sum := h.Sum(nil)
+		if actual := fmt.Sprintf("%X", sum); actual != v.output {
+			t.Errorf("bad hash (%d, keyed %v): expected=%s, actual=%s", v.length, v.keyed, v.output, actual)
+		}
+
+		full := NewP()
+		if v.keyed {
+			full = NewPKeyed(key)
+		}
+		full.Write(input)
+		if bytes.Equal(sum, full.Sum(nil)[:16]) {
+			t.Errorf("sized hash (%d) is a truncation of the full-length one", v.length)
+		}
+	}
+}
+
+func TestNewPSizedInvalid(t *testing.T) {
+	for _, size := range []int{0, Size + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for size %d", size)
+				}
+			}()
+			NewPSized(size, nil)
+		}()
+	}
+}
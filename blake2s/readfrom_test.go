@@ -0,0 +1,56 @@
+package blake2s
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadFrom(t *testing.T) {
+	input := strings.Repeat("the quick brown fox ", 5000)
+
+	h := New()
+	n, err := h.(io.ReaderFrom).ReadFrom(strings.NewReader(input))
+	if n != int64(len(input)) || err != nil {
+		t.Fatalf("ReadFrom: n=%d, err=%v", n, err)
+	}
+
+	ref := New()
+	ref.Write([]byte(input))
+	if actual, expected := h.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+	}
+}
+
+type errReader struct {
+	data []byte
+	err  error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestReadFromError(t *testing.T) {
+	input := []byte(strings.Repeat("x", 1000))
+	readErr := errors.New("read failed")
+
+	h := New()
+	n, err := h.(io.ReaderFrom).ReadFrom(&errReader{input, readErr})
+	if n != int64(len(input)) || err != readErr {
+		t.Fatalf("ReadFrom: n=%d, err=%v", n, err)
+	}
+
+	ref := New()
+	ref.Write(input)
+	if actual, expected := h.Sum(nil), ref.Sum(nil); !bytes.Equal(actual, expected) {
+		t.Errorf("bad hash: expected=%X, actual=%X", expected, actual)
+	}
+}
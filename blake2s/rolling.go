@@ -0,0 +1,45 @@
+package blake2s
+
+import "encoding/binary"
+
+// Rolling computes a Blake2s checksum over a sliding window of the most
+// recent bytes of a stream.
+//
+// Blake2s cannot remove a byte from its state, so every call to Roll
+// recomputes the checksum of the whole window. The cost per byte is thus
+// proportional to the window size.
+type Rolling struct {
+	d      *digest
+	window []byte
+	pos    int
+	full   bool
+}
+
+// NewRolling returns a Rolling over a window of the given number of bytes.
+func NewRolling(window int) *Rolling {
+	if window < 1 {
+		panic("blake2s: invalid rolling window size")
+	}
+	return &Rolling{d: New().(*digest), window: make([]byte, window)}
+}
+
+// Roll adds b to the window, dropping the oldest byte once the window is
+// full, and returns the first 4 bytes of the checksum of the window as a
+// little-endian uint32. Until the window fills up, the checksum covers
+// all bytes rolled in so far.
+func (r *Rolling) Roll(b byte) uint32 {
+	r.window[r.pos] = b
+	r.pos++
+	if r.pos == len(r.window) {
+		r.pos = 0
+		r.full = true
+	}
+
+	r.d.Reset()
+	if r.full {
+		r.d.Write(r.window[r.pos:])
+	}
+	r.d.Write(r.window[:r.pos])
+	sum := r.d.checkSum()
+	return binary.LittleEndian.Uint32(sum[:])
+}
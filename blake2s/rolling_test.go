@@ -0,0 +1,30 @@
+package blake2s
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestRolling(t *testing.T) {
+	input := make([]byte, 300)
+	for i := range input {
+		input[i] = byte(i * 7)
+	}
+
+	for _, window := range []int{1, 16, 64, 65} {
+		r := NewRolling(window)
+		for i, b := range input {
+			start := i + 1 - window
+			if start < 0 {
+				start = 0
+			}
+			h := New()
+			h.Write(input[start : i+1])
+			expected := binary.LittleEndian.Uint32(h.Sum(nil))
+
+			if actual := r.Roll(b); actual != expected {
+				t.Fatalf("bad rolling hash (window %d, pos %d): expected=%#x, actual=%#x", window, i, expected, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package blake2s
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+)
+
+// EmptyHash256 is the Blake2s checksum of the empty input. It is
+// computed and checked by SelfTest when the package is initialized.
+var EmptyHash256 []byte
+
+// Known answers used by SelfTest.
+var selfTestVectors = []struct {
+	input  string
+	output string
+}{
+	{"", "69217a3079908094e11121d042354a7c1f55b6482ca1a51e1b250dfd1ed0eef9"},
+	{"abc", "508c5e8c327c14e2e1a72ba34eeb452f37458b209ed63a294d999b4c86675982"},
+}
+
+func init() {
+	if err := SelfTest(); err != nil {
+		panic(err)
+	}
+	EmptyHash256 = New().Sum(nil)
+}
+
+// SelfTest checks the Blake2s implementation against known answers.
+func SelfTest() error {
+	for _, v := range selfTestVectors {
+		expected, _ := hex.DecodeString(v.output)
+		h := New()
+		h.Write([]byte(v.input))
+		if !bytes.Equal(h.Sum(nil), expected) {
+			return errors.New("blake2s: self-test failed")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package blake2s
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEmptyHash256(t *testing.T) {
+	h := New()
+	h.Write(nil)
+	if d := h.Sum(nil); !bytes.Equal(d, EmptyHash256) {
+		t.Errorf("bad empty hash: expected=%X, actual=%X", EmptyHash256, d)
+	}
+	if len(EmptyHash256) != 32 {
+		t.Errorf("bad empty hash length: %d", len(EmptyHash256))
+	}
+}
+
+// rfc7693Seq returns the deterministic test input of length n for seed,
+// generated as in the self-test of RFC 7693, Appendix E.
+func rfc7693Seq(n int, seed uint32) []byte {
+	a, b := 0xDEAD4BAD*seed, uint32(1)
+	out := make([]byte, n)
+	for i := range out {
+		a, b = b, a+b
+		out[i] = byte(b >> 24)
+	}
+	return out
+}
+
+// TestRFC7693SelfTest hashes the unkeyed and keyed digests of every
+// combination of digest and input length from RFC 7693, Appendix E, into
+// one 32-byte digest-of-digests and checks it against the published value.
+func TestRFC7693SelfTest(t *testing.T) {
+	root, _ := NewWith(WithSize(32))
+	for _, size := range []int{16, 20, 28, 32} {
+		for _, n := range []int{0, 3, 64, 65, 255, 1024} {
+			input := rfc7693Seq(n, uint32(n))
+
+			h, _ := NewWith(WithSize(size))
+			h.Write(input)
+			root.Write(h.Sum(nil))
+
+			h, _ = NewWith(WithSize(size), WithKey(rfc7693Seq(size, uint32(size))))
+			h.Write(input)
+			root.Write(h.Sum(nil))
+		}
+	}
+	actual := fmt.Sprintf("%X", root.Sum(nil))
+	expected := "6A411F08CE25ADCDFB02ABA641451CEC53C598B24F4FC787FBDC88797F4C1DFE"
+	if actual != expected {
+		t.Errorf("bad digest of digests: expected=%s, actual=%s", expected, actual)
+	}
+}
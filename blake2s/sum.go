@@ -0,0 +1,18 @@
+package blake2s
+
+import "hash"
+
+// New256 returns a new hash.Hash computing the 32-byte Blake2s checksum.
+// Together with Sum256 it mirrors the crypto/sha256 API, so code using
+// sha256.New and sha256.Sum256 can switch by renaming the package.
+func New256() hash.Hash {
+	return New()
+}
+
+// Sum256 returns the 32-byte Blake2s checksum of data.
+func Sum256(data []byte) [Size]byte {
+	d := newDigest()
+	d.Reset()
+	d.Write(data)
+	return d.checkSum()
+}
@@ -0,0 +1,38 @@
+package blake2s
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"testing"
+)
+
+// The sha256 function values give the signatures that New256 and Sum256
+// must match to be drop-in replacements.
+var (
+	_ func() hash.Hash        = sha256.New
+	_ func([]byte) [32]byte   = sha256.Sum256
+	_ func() hash.Hash        = New256
+	_ func([]byte) [Size]byte = Sum256
+	_ [sha256.Size]byte       = [Size]byte{}
+	_ [sha256.BlockSize]byte  = [BlockSize]byte{}
+)
+
+func TestSum256(t *testing.T) {
+	for _, v := range vectors2s {
+		input := make([]byte, v.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+		sum := Sum256(input)
+		if actual := fmt.Sprintf("%X", sum); actual != v.output {
+			t.Errorf("bad hash (%d): expected=%s, actual=%s", v.length, v.output, actual)
+		}
+
+		h := New256()
+		h.Write(input)
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.output {
+			t.Errorf("bad streaming hash (%d): expected=%s, actual=%s", v.length, v.output, actual)
+		}
+	}
+}
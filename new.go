@@ -0,0 +1,25 @@
+package blake2
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/wheelcomplex/blake2-2/blake2b"
+	"github.com/wheelcomplex/blake2-2/blake2s"
+)
+
+// New returns a new hash.Hash computing a size byte BLAKE2 checksum. It
+// uses BLAKE2s for sizes up to 32 bytes, including 32 itself, where
+// BLAKE2s is the standard 256-bit variant and the faster one on 32-bit
+// platforms, and BLAKE2b for sizes up to 64 bytes. Note that a digest of
+// one variant is unrelated to a digest of the same size of the other.
+func New(size int) (hash.Hash, error) {
+	switch {
+	case size < 1 || size > blake2b.Size:
+		return nil, errors.New("blake2: invalid digest size")
+	case size <= blake2s.Size:
+		return blake2s.NewWith(blake2s.WithSize(size))
+	default:
+		return blake2b.NewWith(blake2b.WithSize(size))
+	}
+}
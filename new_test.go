@@ -0,0 +1,39 @@
+package blake2_test
+
+import (
+	"fmt"
+	"testing"
+
+	blake2 "github.com/wheelcomplex/blake2-2"
+)
+
+func TestNew(t *testing.T) {
+	for _, v := range []struct {
+		size     int
+		expected string
+	}{
+		// BLAKE2s up to and including 32 bytes.
+		{16, "AA4938119B1DC7B87CBAD0FFD200D0AE"},
+		{32, "508C5E8C327C14E2E1A72BA34EEB452F37458B209ED63A294D999B4C86675982"},
+		// BLAKE2b above.
+		{33, "F7BB660EC10C1B537A53FF432791F8A34C09E9ECFCA84288BBA1EE39AFEC290D63"},
+		{64, "BA80A53F981C4D0D6A2797B69F12F6E94C212F14685AC4B74B12BB6FDBFFA2D17D87C5392AAB792DC252D5DE4533CC9518D38AA8DBF1925AB92386EDD4009923"},
+	} {
+		h, err := blake2.New(v.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Write([]byte("abc"))
+		if actual := fmt.Sprintf("%X", h.Sum(nil)); actual != v.expected {
+			t.Errorf("bad hash (%d): expected=%s, actual=%s", v.size, v.expected, actual)
+		}
+	}
+}
+
+func TestNewInvalidSize(t *testing.T) {
+	for _, size := range []int{-1, 0, 65} {
+		if _, err := blake2.New(size); err == nil {
+			t.Errorf("expected error for size %d", size)
+		}
+	}
+}
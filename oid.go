@@ -0,0 +1,33 @@
+package blake2
+
+import (
+	"crypto"
+	"encoding/asn1"
+)
+
+// Object identifiers of the BLAKE2 digests from RFC 7693, to be used in
+// ASN.1 structures such as the DigestInfo of a signature. They must not
+// be modified.
+var (
+	OIDBlake2b256 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 1722, 12, 2, 1, 8}
+	OIDBlake2b384 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 1722, 12, 2, 1, 12}
+	OIDBlake2b512 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 1722, 12, 2, 1, 16}
+	OIDBlake2s256 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 1722, 12, 2, 2, 8}
+)
+
+var hashOIDs = map[crypto.Hash]asn1.ObjectIdentifier{
+	crypto.BLAKE2b_256: OIDBlake2b256,
+	crypto.BLAKE2b_384: OIDBlake2b384,
+	crypto.BLAKE2b_512: OIDBlake2b512,
+	crypto.BLAKE2s_256: OIDBlake2s256,
+}
+
+// OID returns a copy of the object identifier of the BLAKE2 digest h,
+// and false if h is not a BLAKE2 digest.
+func OID(h crypto.Hash) (asn1.ObjectIdentifier, bool) {
+	oid, ok := hashOIDs[h]
+	if !ok {
+		return nil, false
+	}
+	return append(asn1.ObjectIdentifier(nil), oid...), true
+}
@@ -0,0 +1,55 @@
+package blake2_test
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"testing"
+
+	blake2 "github.com/wheelcomplex/blake2-2"
+	"github.com/wheelcomplex/blake2-2/blake2b"
+)
+
+func TestOID(t *testing.T) {
+	for _, v := range []struct {
+		h        crypto.Hash
+		expected string
+	}{
+		{crypto.BLAKE2b_256, "1.3.6.1.4.1.1722.12.2.1.8"},
+		{crypto.BLAKE2b_384, "1.3.6.1.4.1.1722.12.2.1.12"},
+		{crypto.BLAKE2b_512, "1.3.6.1.4.1.1722.12.2.1.16"},
+		{crypto.BLAKE2s_256, "1.3.6.1.4.1.1722.12.2.2.8"},
+	} {
+		oid, ok := blake2.OID(v.h)
+		if !ok || oid.String() != v.expected {
+			t.Errorf("bad OID (%v): expected=%s, actual=%s", v.h, v.expected, oid)
+		}
+	}
+	if _, ok := blake2.OID(crypto.SHA256); ok {
+		t.Error("unexpected OID for SHA-256")
+	}
+}
+
+func TestDigestInfo(t *testing.T) {
+	oid, _ := blake2.OID(crypto.BLAKE2b_512)
+	h := blake2b.New()
+	h.Write([]byte("abc"))
+
+	info := struct {
+		Algorithm pkix.AlgorithmIdentifier
+		Digest    []byte
+	}{
+		pkix.AlgorithmIdentifier{Algorithm: oid, Parameters: asn1.NullRawValue},
+		h.Sum(nil),
+	}
+	der, err := asn1.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := fmt.Sprintf("%X", der)
+	expected := "3053300F060B2B060104018D3A0C02011005000440BA80A53F981C4D0D6A2797B69F12F6E94C212F14685AC4B74B12BB6FDBFFA2D17D87C5392AAB792DC252D5DE4533CC9518D38AA8DBF1925AB92386EDD4009923"
+	if actual != expected {
+		t.Errorf("bad DigestInfo: expected=%s, actual=%s", expected, actual)
+	}
+}
@@ -0,0 +1,52 @@
+package blake2
+
+import (
+	"hash"
+
+	"github.com/wheelcomplex/blake2-2/blake2b"
+	"github.com/wheelcomplex/blake2-2/blake2s"
+)
+
+type recommendation struct {
+	alg  string
+	size int
+}
+
+var recommendations = map[string]recommendation{
+	// Content addresses need collision resistance, and 256 bits keep
+	// identifiers short.
+	"content-id": {"blake2b", 32},
+	// Keyed tags of 256 bits; truncating them further weakens forgery
+	// resistance.
+	"mac": {"blake2b", 32},
+	// File checksums, compatible with the default of b2sum.
+	"checksum": {"blake2b", 64},
+	// Short identifiers such as cache keys, where inputs are not chosen
+	// by an attacker; BLAKE2s is faster on 32-bit platforms.
+	"fingerprint": {"blake2s", 16},
+}
+
+// defaultRecommendation is returned for unknown purposes.
+var defaultRecommendation = recommendation{"blake2b", 64}
+
+// Recommend returns an algorithm, "blake2b" or "blake2s", and a digest
+// size in bytes suited to purpose: "content-id", "mac", "checksum" or
+// "fingerprint". Unknown purposes get the full-length BLAKE2b-512.
+func Recommend(purpose string) (alg string, size int) {
+	r, ok := recommendations[purpose]
+	if !ok {
+		r = defaultRecommendation
+	}
+	return r.alg, r.size
+}
+
+// NewFor returns a new hash.Hash configured as recommended by Recommend
+// for purpose, keyed with key if it is not empty. It returns an error if
+// key is too long for the algorithm.
+func NewFor(purpose string, key []byte) (hash.Hash, error) {
+	alg, size := Recommend(purpose)
+	if alg == "blake2s" {
+		return blake2s.NewWith(blake2s.WithSize(size), blake2s.WithKey(key))
+	}
+	return blake2b.NewWith(blake2b.WithSize(size), blake2b.WithKey(key))
+}
@@ -0,0 +1,50 @@
+package blake2_test
+
+import (
+	"bytes"
+	"testing"
+
+	blake2 "github.com/wheelcomplex/blake2-2"
+	"github.com/wheelcomplex/blake2-2/blake2b"
+)
+
+func TestRecommend(t *testing.T) {
+	for _, v := range []struct {
+		purpose string
+		alg     string
+		size    int
+	}{
+		{"content-id", "blake2b", 32},
+		{"mac", "blake2b", 32},
+		{"checksum", "blake2b", 64},
+		{"fingerprint", "blake2s", 16},
+		{"something else", "blake2b", 64},
+		{"", "blake2b", 64},
+	} {
+		if alg, size := blake2.Recommend(v.purpose); alg != v.alg || size != v.size {
+			t.Errorf("bad recommendation for %q: expected=%s/%d, actual=%s/%d", v.purpose, v.alg, v.size, alg, size)
+		}
+		h, err := blake2.NewFor(v.purpose, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Size() != v.size {
+			t.Errorf("bad size for %q: expected=%d, actual=%d", v.purpose, v.size, h.Size())
+		}
+	}
+}
+
+func TestNewForKeyed(t *testing.T) {
+	key := []byte("secret key")
+	h, err := blake2.NewFor("mac", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Write([]byte("message"))
+	if actual, expected := h.Sum(nil), blake2b.MAC(key, []byte("message"), 32); !bytes.Equal(actual, expected) {
+		t.Errorf("bad tag: expected=%X, actual=%X", expected, actual)
+	}
+	if _, err := blake2.NewFor("mac", make([]byte, blake2b.KeySize+1)); err == nil {
+		t.Error("expected error for oversized key")
+	}
+}